@@ -0,0 +1,60 @@
+package check
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/RaphSku/notewolfy/internal/commands"
+	"github.com/RaphSku/notewolfy/internal/console"
+	"github.com/spf13/cobra"
+)
+
+type CheckCmd struct{}
+
+func NewCheckCmd() *CheckCmd {
+	return &CheckCmd{}
+}
+
+func (cc *CheckCmd) GetCheckCmd() *cobra.Command {
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Checks the notes of the active workspace.",
+	}
+	checkLinksCmd := &cobra.Command{
+		Use:   "links",
+		Short: "Reports broken links in the notes of the active workspace.",
+		Long:  `This will walk every note of the active workspace and report relative markdown links to missing files, #heading anchors without a matching heading and [[wiki links]] to missing notes, each with its file, line and a suggested fix. The command exits with a non-zero exit code if any link is broken.`,
+		Args:  cobra.NoArgs,
+		Run:   cc.runCheckLinksCmd,
+	}
+	checkCmd.AddCommand(checkLinksCmd)
+
+	return checkCmd
+}
+
+func (cc *CheckCmd) runCheckLinksCmd(cmd *cobra.Command, args []string) {
+	brokenLinks, err := cc.checkLinks()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if brokenLinks > 0 {
+		os.Exit(1)
+	}
+}
+
+func (cc *CheckCmd) checkLinks() (int, error) {
+	mmf, err := console.GetMetadataNoteWolfyFileHandle()
+	if err != nil {
+		return 0, err
+	}
+	if err := mmf.Lock(); err != nil {
+		return 0, err
+	}
+	defer mmf.Unlock()
+
+	brokenLinks, err := commands.CheckLinks(mmf)
+	fmt.Println()
+
+	return brokenLinks, err
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/RaphSku/notewolfy/internal/commands"
+	"github.com/RaphSku/notewolfy/internal/console"
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/spf13/cobra"
+)
+
+type ExportCmd struct {
+	format     string
+	withLinks  bool
+	outputPath string
+}
+
+func NewExportCmd() *ExportCmd {
+	return &ExportCmd{}
+}
+
+func (ec *ExportCmd) GetExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Exports the active workspace.",
+	}
+	exportGraphCmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Exports the node hierarchy of the active workspace as a graph.",
+		Long:  `This will render the node hierarchy of the active workspace with the markdown files as leaves, either in the DOT format of Graphviz or as a Mermaid flowchart. With --links the wiki links and relative markdown links between notes are added as dashed edges. The graph is written to stdout unless an output file is given.`,
+		Args:  cobra.NoArgs,
+		Run:   ec.runExportGraphCmd,
+	}
+	exportGraphCmd.Flags().StringVar(&ec.format, "format", structure.GraphFormatDot, "format of the graph, either 'dot' or 'mermaid'")
+	exportGraphCmd.Flags().BoolVar(&ec.withLinks, "links", false, "add the links between notes as edges")
+	exportGraphCmd.Flags().StringVarP(&ec.outputPath, "output", "o", "", "file to write the graph to instead of stdout")
+	exportCmd.AddCommand(exportGraphCmd)
+
+	return exportCmd
+}
+
+func (ec *ExportCmd) runExportGraphCmd(cmd *cobra.Command, args []string) {
+	graph, err := ec.exportGraph()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Print(graph)
+}
+
+func (ec *ExportCmd) exportGraph() (string, error) {
+	mmf, err := console.GetMetadataNoteWolfyFileHandle()
+	if err != nil {
+		return "", err
+	}
+	if err := mmf.Lock(); err != nil {
+		return "", err
+	}
+	defer mmf.Unlock()
+
+	return commands.ExportGraph(mmf, ec.format, ec.withLinks, ec.outputPath)
+}
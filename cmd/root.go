@@ -4,7 +4,13 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/RaphSku/notewolfy/cmd/check"
+	"github.com/RaphSku/notewolfy/cmd/export"
+	"github.com/RaphSku/notewolfy/cmd/search"
+	"github.com/RaphSku/notewolfy/cmd/store"
+	"github.com/RaphSku/notewolfy/cmd/sync"
 	"github.com/RaphSku/notewolfy/cmd/version"
+	"github.com/RaphSku/notewolfy/internal/config"
 	"github.com/RaphSku/notewolfy/internal/console"
 	"github.com/RaphSku/notewolfy/internal/logging"
 	"github.com/spf13/cobra"
@@ -12,7 +18,8 @@ import (
 )
 
 type CLI struct {
-	logger *zap.Logger
+	logger    *zap.Logger
+	overrides config.Overrides
 
 	rootCmd *cobra.Command
 }
@@ -27,19 +34,35 @@ func (cli *CLI) Run() {
 		Short: "notewolfy, a minimalistic note taking console application!",
 		Long:  `notewolfy is a minimalistic note taking console application that allows you to organize and manage your markdown notes!`,
 		Run:   cli.runNotewolfyCommand,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			console.Configure(cli.overrides)
+		},
 	}
 
 	// --- ROOT CMD
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.PersistentFlags().StringVar(&cli.overrides.ConfigPath, "config", "", "path to the config file (default $XDG_CONFIG_HOME/notewolfy/config.yaml, env NOTEWOLFY_CONFIG)")
+	rootCmd.PersistentFlags().StringVar(&cli.overrides.Backend, "backend", "", "metadata backend, either 'json' or 'bolt' (env NOTEWOLFY_BACKEND)")
 	cli.rootCmd = rootCmd
 
 	// --- SUB CMD
 	versionCmd := version.NewVersionCmd().GetVersionCmd()
 	cli.rootCmd.AddCommand(versionCmd)
+	syncCmd := sync.NewSyncCmd().GetSyncCmd()
+	cli.rootCmd.AddCommand(syncCmd)
+	searchCmd := search.NewSearchCmd().GetSearchCmd()
+	cli.rootCmd.AddCommand(searchCmd)
+	checkCmd := check.NewCheckCmd().GetCheckCmd()
+	cli.rootCmd.AddCommand(checkCmd)
+	exportCmd := export.NewExportCmd().GetExportCmd()
+	cli.rootCmd.AddCommand(exportCmd)
+	migrateStoreCmd := store.NewMigrateStoreCmd(&cli.overrides).GetMigrateStoreCmd()
+	cli.rootCmd.AddCommand(migrateStoreCmd)
 
 	// --- EXECUTE
 	if err := cli.rootCmd.Execute(); err != nil {
 		fmt.Printf("CLI failed to run due to %v\n", err)
+		os.Exit(1)
 	}
 }
 
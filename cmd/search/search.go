@@ -0,0 +1,55 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/RaphSku/notewolfy/internal/commands"
+	"github.com/RaphSku/notewolfy/internal/console"
+	"github.com/spf13/cobra"
+)
+
+type SearchCmd struct {
+	allWorkspaces bool
+}
+
+func NewSearchCmd() *SearchCmd {
+	return &SearchCmd{}
+}
+
+func (sc *SearchCmd) GetSearchCmd() *cobra.Command {
+	searchCmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Searches the content of the markdown files.",
+		Long:  `This will search the markdown files of the active workspace for the query, ignoring case, and print every matching line with its node path, filename and line number. The results are numbered and can be opened with 'edit #<n>' inside of notewolfy.`,
+		Args:  cobra.MinimumNArgs(1),
+		Run:   sc.runSearchCmd,
+	}
+	searchCmd.Flags().BoolVar(&sc.allWorkspaces, "all", false, "search the markdown files of all workspaces")
+
+	return searchCmd
+}
+
+func (sc *SearchCmd) runSearchCmd(cmd *cobra.Command, args []string) {
+	if err := sc.searchNotes(strings.Join(args, " ")); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func (sc *SearchCmd) searchNotes(query string) error {
+	mmf, err := console.GetMetadataNoteWolfyFileHandle()
+	if err != nil {
+		return err
+	}
+	if err := mmf.Lock(); err != nil {
+		return err
+	}
+	defer mmf.Unlock()
+
+	_, err = commands.SearchNotes(mmf, query, sc.allWorkspaces)
+	fmt.Println()
+
+	return err
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/RaphSku/notewolfy/internal/config"
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/spf13/cobra"
+)
+
+type MigrateStoreCmd struct {
+	overrides *config.Overrides
+}
+
+func NewMigrateStoreCmd(overrides *config.Overrides) *MigrateStoreCmd {
+	return &MigrateStoreCmd{
+		overrides: overrides,
+	}
+}
+
+func (msc *MigrateStoreCmd) GetMigrateStoreCmd() *cobra.Command {
+	migrateStoreCmd := &cobra.Command{
+		Use:   "migrate-store <from> <to>",
+		Short: "Copies the notewolfy metadata from one storage backend to another.",
+		Long:  `This will copy all workspaces, nodes and markdown files from the metadata of the backend <from> into the empty metadata of the backend <to>. The supported backends are 'json' and 'bolt', afterwards select the new backend with 'backend' in your config file, the --backend flag or the NOTEWOLFY_BACKEND environment variable.`,
+		Args:  cobra.ExactArgs(2),
+		Run:   msc.runMigrateStoreCmd,
+	}
+
+	return migrateStoreCmd
+}
+
+func (msc *MigrateStoreCmd) runMigrateStoreCmd(cmd *cobra.Command, args []string) {
+	sourceConfig, err := config.Load(config.Overrides{ConfigPath: msc.overrides.ConfigPath, Backend: args[0]})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	targetConfig, err := config.Load(config.Overrides{ConfigPath: msc.overrides.ConfigPath, Backend: args[1]})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if sourceConfig.MetadataFilePath == targetConfig.MetadataFilePath {
+		fmt.Println("Source and target backend have to be different!")
+		os.Exit(1)
+	}
+
+	sourceStore, err := structure.NewMetadataStore(sourceConfig)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	targetStore, err := structure.NewMetadataStore(targetConfig)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := structure.MigrateStore(sourceStore, targetStore); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Migrated the metadata from %s to %s, set 'backend: %s' in your config file to use it\n", sourceStore.Path(), targetStore.Path(), args[1])
+}
@@ -0,0 +1,59 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/RaphSku/notewolfy/internal/commands"
+	"github.com/RaphSku/notewolfy/internal/console"
+	"github.com/spf13/cobra"
+)
+
+type SyncCmd struct {
+	dryRun    bool
+	assumeYes bool
+}
+
+func NewSyncCmd() *SyncCmd {
+	return &SyncCmd{}
+}
+
+func (sc *SyncCmd) GetSyncCmd() *cobra.Command {
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Reconciles the notewolfy metadata with the filesystem.",
+		Long:  `This will compare every workspace with its directory on disk, report added, missing and moved nodes and markdown files and apply the changes after confirmation. With --dry-run the command exits with a non-zero exit code if the metadata is out of sync.`,
+		Run:   sc.runSyncCmd,
+	}
+	syncCmd.Flags().BoolVar(&sc.dryRun, "dry-run", false, "only report the differences, exit with code 1 if there are any")
+	syncCmd.Flags().BoolVarP(&sc.assumeYes, "yes", "y", false, "apply the changes without asking for confirmation")
+
+	return syncCmd
+}
+
+func (sc *SyncCmd) runSyncCmd(cmd *cobra.Command, args []string) {
+	hasChanges, err := sc.syncWorkspaces()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if sc.dryRun && hasChanges {
+		os.Exit(1)
+	}
+}
+
+func (sc *SyncCmd) syncWorkspaces() (bool, error) {
+	mmf, err := console.GetMetadataNoteWolfyFileHandle()
+	if err != nil {
+		return false, err
+	}
+	if err := mmf.Lock(); err != nil {
+		return false, err
+	}
+	defer mmf.Unlock()
+
+	hasChanges, err := commands.SyncWorkspaces(mmf, sc.dryRun, sc.assumeYes, os.Stdin)
+	fmt.Println()
+
+	return hasChanges, err
+}
@@ -4,7 +4,9 @@ go 1.24.2
 
 require (
 	github.com/google/uuid v1.6.0
+	go.etcd.io/bbolt v1.3.10
 	go.uber.org/zap v1.27.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -14,7 +16,6 @@ require (
 	github.com/spf13/pflag v1.0.5 // indirect
 	golang.org/x/sys v0.21.0 // indirect
 	golang.org/x/term v0.21.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+)
+
+type CheckLinksStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (cls *CheckLinksStrategy) Run() error {
+	if cls.statement != "check links" {
+		return fmt.Errorf("\n\rPlease use 'check links'!")
+	}
+
+	_, err := CheckLinks(cls.mmf)
+	return err
+}
+
+// CheckLinks prints the broken links of the active workspace with a suggested fix and returns their number.
+func CheckLinks(mmf *structure.MetadataNoteWolfyFileHandle) (int, error) {
+	brokenLinks, err := mmf.CheckLinks()
+	if err != nil {
+		return 0, fmt.Errorf("\n\rCould not check the links, %v!", err)
+	}
+	if len(brokenLinks) == 0 {
+		fmt.Print("\n\rAll links are valid!")
+		return 0, nil
+	}
+
+	for _, brokenLink := range brokenLinks {
+		fmt.Printf("\n\r%s:%d: %s %s", path.Join(brokenLink.Note.NodePath, brokenLink.Note.Markdown.Filename), brokenLink.Line, brokenLink.Link, brokenLink.Problem)
+		if brokenLink.Suggestion != "" {
+			fmt.Printf(", did you mean '%s'?", brokenLink.Suggestion)
+		}
+	}
+	fmt.Printf("\n\rFound %d broken links!", len(brokenLinks))
+
+	return len(brokenLinks), nil
+}
@@ -42,6 +42,12 @@ func MatchStatementToCommand(mmf *structure.MetadataNoteWolfyFileHandle, stateme
 	if strategy == nil {
 		return
 	}
+	if err := mmf.Lock(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer mmf.Unlock()
+
 	context := NewContext(strategy)
 	if err := context.RunStrategy(); err != nil {
 		fmt.Println(err)
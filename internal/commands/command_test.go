@@ -8,10 +8,12 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/RaphSku/notewolfy/internal/commands"
 	"github.com/RaphSku/notewolfy/internal/structure"
@@ -44,6 +46,12 @@ func CleanUpFile(filePath string) {
 		fmt.Println("Could not remove metadata file! Please clean it up yourself!")
 		os.Exit(1)
 	}
+	os.Remove(filePath + ".lock")
+	os.Remove(filePath + ".journal")
+	os.RemoveAll(filePath + ".trash")
+	os.Remove(filePath + ".results")
+	os.Remove(filePath + ".index")
+	os.Remove(filePath + ".index.log")
 }
 
 func captureStdOutput(f func()) (string, error) {
@@ -70,6 +78,25 @@ func captureStdOutput(f func()) (string, error) {
 	return out, nil
 }
 
+// createTestWorkspace creates the metadata of the config together with the workspace 'test' in a temporary directory,
+// a unique metadata file is used if the config does not specify one.
+func createTestWorkspace(t *testing.T, config *structure.Config) (*structure.MetadataNoteWolfyFileHandle, string) {
+	t.Helper()
+	if config.MetadataFilePath == "" {
+		config.MetadataFilePath = createUniquePath("./.notewolfy")
+	}
+	t.Cleanup(func() {
+		CleanUpFile(config.MetadataFilePath)
+	})
+	workspacePath := filepath.Join(t.TempDir(), "test")
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	commands.MatchStatementToCommand(mmf, fmt.Sprintf("create workspace test %s", workspacePath))
+
+	return mmf, workspacePath
+}
+
 func TestMatchStatementToCreateWorkspaceCommand(t *testing.T) {
 	t.Parallel()
 
@@ -106,7 +133,7 @@ func TestMatchStatementToCreateWorkspaceCommand(t *testing.T) {
 			if tc.want {
 				expandedPath, err := utility.ExpandRelativePaths(tc.path)
 				assert.NoError(t, err)
-				defer os.Remove(expandedPath)
+				defer os.RemoveAll(expandedPath)
 
 				assert.NoError(t, err)
 				assert.True(t, exists)
@@ -175,16 +202,17 @@ func TestMatchStatementToDeleteWorkspaceCommand(t *testing.T) {
 			err = os.Mkdir(workspacePath, os.ModePerm)
 			assert.NoError(t, err)
 			workspaceNode := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: "test",
 				Path: workspacePath,
 			}
 			mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
 			mmf.ActiveWorkspace = workspaceNode.Name
-			mmf.ActiveNode = workspaceNode.Name
+			mmf.ActiveNode = workspaceNode.ID
 			err = mmf.Save()
 			assert.NoError(t, err)
 
-			defer os.Remove(workspaceNode.Path)
+			defer os.RemoveAll(workspaceNode.Path)
 
 			commands.MatchStatementToCommand(mmf, tc.statement)
 			_, err = fileOrDirectoryExists(tc.path)
@@ -251,12 +279,13 @@ func TestMatchStatementToCreateNodeCommand(t *testing.T) {
 			err = os.Mkdir(workspacePath, os.ModePerm)
 			assert.NoError(t, err)
 			workspaceNode := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: "Workspace",
 				Path: workspacePath,
 			}
 			mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
 			mmf.ActiveWorkspace = workspaceNode.Name
-			mmf.ActiveNode = workspaceNode.Name
+			mmf.ActiveNode = workspaceNode.ID
 			err = mmf.Save()
 			assert.NoError(t, err)
 
@@ -266,14 +295,14 @@ func TestMatchStatementToCreateNodeCommand(t *testing.T) {
 			exists, err := fileOrDirectoryExists(nodePath)
 			if tc.want {
 				os.Remove(nodePath)
-				os.Remove(workspacePath)
+				os.RemoveAll(workspacePath)
 
 				assert.NoError(t, err)
 				assert.True(t, exists)
 
 				return
 			}
-			os.Remove(workspacePath)
+			os.RemoveAll(workspacePath)
 			if assert.Error(t, err) {
 				if _, ok := err.(*fs.PathError); ok {
 					assert.True(t, ok)
@@ -330,22 +359,24 @@ func TestMatchStatementToDeleteNodeCommand(t *testing.T) {
 			err = os.Mkdir(workspacePath, os.ModePerm)
 			assert.NoError(t, err)
 			workspaceNode := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: "Workspace",
 				Path: workspacePath,
 			}
 			mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
 			mmf.ActiveWorkspace = workspaceNode.Name
-			mmf.ActiveNode = workspaceNode.Name
+			mmf.ActiveNode = workspaceNode.ID
 			err = mmf.Save()
 			assert.NoError(t, err)
 
-			defer os.Remove(workspacePath)
+			defer os.RemoveAll(workspacePath)
 
 			// Let's create a node that can be deleted
 			nodePath := filepath.Join(workspacePath, tc.nodeName)
 			err = os.Mkdir(nodePath, os.ModePerm)
 			assert.NoError(t, err)
 			node := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: tc.nodeName,
 				Path: nodePath,
 			}
@@ -418,12 +449,13 @@ func TestMatchStatementToCreateMarkdownFile(t *testing.T) {
 			err = os.Mkdir(workspacePath, os.ModePerm)
 			assert.NoError(t, err)
 			workspaceNode := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: "Workspace",
 				Path: workspacePath,
 			}
 			mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
 			mmf.ActiveWorkspace = workspaceNode.Name
-			mmf.ActiveNode = workspaceNode.Name
+			mmf.ActiveNode = workspaceNode.ID
 			err = mmf.Save()
 			assert.NoError(t, err)
 
@@ -433,14 +465,14 @@ func TestMatchStatementToCreateMarkdownFile(t *testing.T) {
 			exists, err := fileOrDirectoryExists(markdownPath)
 			if tc.want {
 				os.Remove(markdownPath)
-				os.Remove(workspacePath)
+				os.RemoveAll(workspacePath)
 
 				assert.NoError(t, err)
 				assert.True(t, exists)
 
 				return
 			}
-			os.Remove(workspacePath)
+			os.RemoveAll(workspacePath)
 			if assert.Error(t, err) {
 				if _, ok := err.(*fs.PathError); ok {
 					assert.True(t, ok)
@@ -451,6 +483,46 @@ func TestMatchStatementToCreateMarkdownFile(t *testing.T) {
 	}
 }
 
+func TestMatchStatementToCreateMarkdownFileOnDuplicateNodeNames(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := createUniquePath("./.notewolfy")
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+
+	// We need to prepare a workspace with two nodes named 'notes' in different branches
+	workspacePath, err := utility.ExpandRelativePaths(createUniquePath("./tmp"))
+	assert.NoError(t, err)
+	defer os.RemoveAll(workspacePath)
+	workspaceNode := structure.NewNode("Workspace", workspacePath)
+	var notesNodes []*structure.Node
+	for _, branchName := range []string{"A", "B"} {
+		branchNode := structure.NewNode(branchName, filepath.Join(workspacePath, branchName))
+		notesNode := structure.NewNode("notes", filepath.Join(branchNode.Path, "notes"))
+		err = os.MkdirAll(notesNode.Path, os.ModePerm)
+		assert.NoError(t, err)
+		branchNode.Children = append(branchNode.Children, notesNode)
+		workspaceNode.Children = append(workspaceNode.Children, branchNode)
+		notesNodes = append(notesNodes, notesNode)
+	}
+	mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
+	mmf.ActiveWorkspace = workspaceNode.Name
+	mmf.ActiveNode = notesNodes[1].ID
+	err = mmf.Save()
+	assert.NoError(t, err)
+
+	commands.MatchStatementToCommand(mmf, "create md example")
+	assert.FileExists(t, filepath.Join(notesNodes[1].Path, "example.md"))
+	assert.NoFileExists(t, filepath.Join(notesNodes[0].Path, "example.md"))
+	assert.Equal(t, 1, len(mmf.FindNode(notesNodes[1].ID).Markdowns))
+	assert.Equal(t, 0, len(mmf.FindNode(notesNodes[0].ID).Markdowns))
+}
+
 func TestMatchStatementToDeleteMarkdownFile(t *testing.T) {
 	t.Parallel()
 
@@ -497,16 +569,17 @@ func TestMatchStatementToDeleteMarkdownFile(t *testing.T) {
 			err = os.Mkdir(workspacePath, os.ModePerm)
 			assert.NoError(t, err)
 			workspaceNode := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: "Workspace",
 				Path: workspacePath,
 			}
 			mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
 			mmf.ActiveWorkspace = workspaceNode.Name
-			mmf.ActiveNode = workspaceNode.Name
+			mmf.ActiveNode = workspaceNode.ID
 			err = mmf.Save()
 			assert.NoError(t, err)
 
-			defer os.Remove(workspacePath)
+			defer os.RemoveAll(workspacePath)
 
 			// We need to create a Markdown file that we can delete
 			markdown := &structure.Markdown{
@@ -580,20 +653,22 @@ func TestMatchStatementToGotoNode(t *testing.T) {
 			err = os.Mkdir(workspacePath, os.ModePerm)
 			assert.NoError(t, err)
 			workspaceNode := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: "Workspace",
 				Path: workspacePath,
 			}
 			mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
 			mmf.ActiveWorkspace = workspaceNode.Name
-			mmf.ActiveNode = workspaceNode.Name
+			mmf.ActiveNode = workspaceNode.ID
 			err = mmf.Save()
 			assert.NoError(t, err)
 
 			// We need to create a node
-			nodePath := filepath.Join(tc.workspacePath, tc.nodeName)
+			nodePath := filepath.Join(workspacePath, tc.nodeName)
 			err = os.Mkdir(nodePath, os.ModePerm)
 			assert.NoError(t, err)
 			node := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: tc.nodeName,
 				Path: nodePath,
 			}
@@ -601,19 +676,19 @@ func TestMatchStatementToGotoNode(t *testing.T) {
 			err = mmf.Save()
 			assert.NoError(t, err)
 
-			defer os.Remove(workspacePath)
+			defer os.RemoveAll(workspacePath)
 			defer os.Remove(nodePath)
 
 			statement := tc.statement
 			commands.MatchStatementToCommand(mmf, statement)
 			if tc.want {
-				assert.Equal(t, node.Name, mmf.ActiveNode)
-				actNode := mmf.FindNode(node.Name)
+				assert.Equal(t, node.ID, mmf.ActiveNode)
+				actNode := mmf.FindNode(node.ID)
 				assert.True(t, reflect.DeepEqual(node, actNode))
 
 				return
 			}
-			assert.Equal(t, workspaceNode.Name, mmf.ActiveNode)
+			assert.Equal(t, workspaceNode.ID, mmf.ActiveNode)
 		})
 	}
 }
@@ -658,6 +733,7 @@ func TestMatchStatementToGoBack(t *testing.T) {
 			err = os.Mkdir(workspacePath, os.ModePerm)
 			assert.NoError(t, err)
 			workspaceNode := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: "Workspace",
 				Path: workspacePath,
 			}
@@ -667,29 +743,30 @@ func TestMatchStatementToGoBack(t *testing.T) {
 			assert.NoError(t, err)
 
 			// We need to create a node
-			nodePath := filepath.Join(tc.workspacePath, tc.nodeName)
+			nodePath := filepath.Join(workspacePath, tc.nodeName)
 			err = os.Mkdir(nodePath, os.ModePerm)
 			assert.NoError(t, err)
 			node := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: tc.nodeName,
 				Path: nodePath,
 			}
-			mmf.ActiveNode = node.Name
+			mmf.ActiveNode = node.ID
 			mmf.Workspaces[0].Children = append(mmf.Workspaces[0].Children, node)
 			err = mmf.Save()
 			assert.NoError(t, err)
 
-			defer os.Remove(workspacePath)
+			defer os.RemoveAll(workspacePath)
 			defer os.Remove(nodePath)
 
 			statement := tc.statement
 			commands.MatchStatementToCommand(mmf, statement)
 			if tc.want {
-				assert.Equal(t, workspaceNode.Name, mmf.ActiveNode)
+				assert.Equal(t, workspaceNode.ID, mmf.ActiveNode)
 
 				return
 			}
-			assert.Equal(t, node.Name, mmf.ActiveNode)
+			assert.Equal(t, node.ID, mmf.ActiveNode)
 		})
 	}
 }
@@ -737,6 +814,7 @@ func TestMatchStatementToOpen(t *testing.T) {
 			err = os.Mkdir(workspacePath, os.ModePerm)
 			assert.NoError(t, err)
 			workspaceNode := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: tc.workspaceName,
 				Path: workspacePath,
 			}
@@ -746,30 +824,31 @@ func TestMatchStatementToOpen(t *testing.T) {
 			assert.NoError(t, err)
 
 			// We need to create a node
-			nodePath := filepath.Join(tc.workspacePath, tc.nodeName)
+			nodePath := filepath.Join(workspacePath, tc.nodeName)
 			err = os.Mkdir(nodePath, os.ModePerm)
 			assert.NoError(t, err)
 			node := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: tc.nodeName,
 				Path: nodePath,
 			}
-			mmf.ActiveNode = node.Name
+			mmf.ActiveNode = node.ID
 			mmf.Workspaces[0].Children = append(mmf.Workspaces[0].Children, node)
 			err = mmf.Save()
 			assert.NoError(t, err)
 
-			defer os.Remove(workspacePath)
+			defer os.RemoveAll(workspacePath)
 			defer os.Remove(nodePath)
 
 			statement := tc.statement
 			commands.MatchStatementToCommand(mmf, statement)
 			if tc.want {
 				assert.Equal(t, workspaceNode.Name, mmf.ActiveWorkspace)
-				assert.Equal(t, workspaceNode.Name, mmf.ActiveNode)
+				assert.Equal(t, workspaceNode.ID, mmf.ActiveNode)
 
 				return
 			}
-			assert.Equal(t, node.Name, mmf.ActiveNode)
+			assert.Equal(t, node.ID, mmf.ActiveNode)
 		})
 	}
 }
@@ -809,10 +888,11 @@ func TestMatchStatementToEdit(t *testing.T) {
 			err = os.Mkdir(workspacePath, os.ModePerm)
 			assert.NoError(t, err)
 			workspaceNode := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: "workspace",
 				Path: workspacePath,
 			}
-			mmf.ActiveNode = workspaceNode.Name
+			mmf.ActiveNode = workspaceNode.ID
 			mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
 			mmf.ActiveWorkspace = workspaceNode.Name
 			err = mmf.Save()
@@ -830,7 +910,7 @@ func TestMatchStatementToEdit(t *testing.T) {
 			err = mmf.Save()
 			assert.NoError(t, err)
 
-			defer os.Remove(workspacePath)
+			defer os.RemoveAll(workspacePath)
 			defer func() {
 				file.Close()
 				os.Remove(file.Name())
@@ -948,11 +1028,12 @@ func TestMatchStatementToList(t *testing.T) {
 			err = os.Mkdir(workspacePath, os.ModePerm)
 			assert.NoError(t, err)
 			workspaceNode := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: tc.workspaceName,
 				Path: workspacePath,
 			}
 			mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
-			mmf.ActiveNode = workspaceNode.Name
+			mmf.ActiveNode = workspaceNode.ID
 			mmf.ActiveWorkspace = workspaceNode.Name
 			err = mmf.Save()
 			assert.NoError(t, err)
@@ -960,11 +1041,13 @@ func TestMatchStatementToList(t *testing.T) {
 			// We need to create nodes and markdown files that ls can display
 			nodePathA := filepath.Join(tc.workspacePath, "A")
 			nodeA := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: "A",
 				Path: nodePathA,
 			}
 			nodePathB := filepath.Join(tc.workspacePath, "B")
 			nodeB := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: "B",
 				Path: nodePathB,
 			}
@@ -977,7 +1060,7 @@ func TestMatchStatementToList(t *testing.T) {
 			err = mmf.Save()
 			assert.NoError(t, err)
 
-			defer os.Remove(workspacePath)
+			defer os.RemoveAll(workspacePath)
 
 			statement := tc.statement
 			actOutput, err := captureStdOutput(func() {
@@ -1032,24 +1115,26 @@ func TestMatchStatementToListWorkspaces(t *testing.T) {
 			workspacePathA, err := utility.ExpandRelativePaths(tc.workspacePaths[0])
 			assert.NoError(t, err)
 			workspaceNodeA := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: tc.workspaceName + "A",
 				Path: workspacePathA,
 			}
 			workspacePathB, err := utility.ExpandRelativePaths(tc.workspacePaths[1])
 			assert.NoError(t, err)
 			workspaceNodeB := &structure.Node{
+				ID:   uuid.New().String(),
 				Name: tc.workspaceName + "B",
 				Path: workspacePathB,
 			}
 			mmf.Workspaces = append(mmf.Workspaces, workspaceNodeA)
 			mmf.Workspaces = append(mmf.Workspaces, workspaceNodeB)
-			mmf.ActiveNode = workspaceNodeA.Name
+			mmf.ActiveNode = workspaceNodeA.ID
 			mmf.ActiveWorkspace = workspaceNodeA.Name
 			err = mmf.Save()
 			assert.NoError(t, err)
 
-			defer os.Remove(workspacePathA)
-			defer os.Remove(workspacePathB)
+			defer os.RemoveAll(workspacePathA)
+			defer os.RemoveAll(workspacePathB)
 
 			statement := tc.statement
 			actOutput, err := captureStdOutput(func() {
@@ -1092,15 +1177,15 @@ func TestMatchStatementToHelp(t *testing.T) {
 	}{
 		"simple help command (1)": {
 			statement: "help ls",
-			expOutput: "\n\rCommand: ls\n\rDescription: ls can be used to list information about the node that you are on, e.g. active node, markdown files on that node, etc.\n\rExample Usage: ls",
+			expOutput: "\n\rCommand: ls [-l | tag:<tag>]\n\rDescription: ls can be used to list information about the node that you are on, e.g. active node, markdown files on that node, etc. With -l the title, last modification, size, word count and tags of every markdown file are shown as well. With tag:<tag> the notes of all nodes of the active workspace that carry the tag are listed with their node paths.\n\rExample Usage: ls -l",
 		},
 		"simple help command (2)": {
 			statement: "help create workspace",
-			expOutput: "\n\rCommand: create workspace <workspaceName> <workspacePath>\n\rDescription: create workspace will create a new workspace for you under the specified name and path that you can choose.\n\rExample Usage: create workspace example /path/to/example",
+			expOutput: "\n\rCommand: create workspace <workspaceName> [<workspacePath>]\n\rDescription: create workspace will create a new workspace for you under the specified name and path that you can choose. If you leave out the path, the workspace is created in the default_workspace_root of your config file.\n\rExample Usage: create workspace example /path/to/example",
 		},
 		"error help command": {
 			statement: "help something",
-			expOutput: "\n\rYou need to specify a valid command, here is a list of possible commands:\n\r- ls\n\r- ls ws\n\r- create workspace\n\r- delete workspace\n\r- create node\n\r- delete node\n\r- create md\n\r- delete md\n\r- edit\n\r- goto\n\r- goback\n\r- open\n\r- version",
+			expOutput: "\n\rYou need to specify a valid command, here is a list of possible commands:\n\r- ls\n\r- ls ws\n\r- ls trash\n\r- tree\n\r- create workspace\n\r- delete workspace\n\r- adopt workspace\n\r- create node\n\r- delete node\n\r- create md\n\r- delete md\n\r- rename md\n\r- rename node\n\r- rename workspace\n\r- mv md\n\r- mv node\n\r- cp md\n\r- cp node\n\r- tag md\n\r- untag md\n\r- tags\n\r- search\n\r- reindex\n\r- find\n\r- links\n\r- backlinks\n\r- check links\n\r- export graph\n\r- edit\n\r- goto\n\r- goback\n\r- open\n\r- undo\n\r- redo\n\r- restore\n\r- empty-trash\n\r- sync\n\r- version",
 		},
 	}
 
@@ -1123,3 +1208,1048 @@ func TestMatchStatementToHelp(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchStatementToSync(t *testing.T) {
+	tests := map[string]struct {
+		statement     string
+		input         string
+		workspacePath string
+		want          bool
+	}{
+		"simple sync command": {
+			statement:     "sync",
+			input:         "y\r",
+			workspacePath: createUniquePath("./tmp"),
+			want:          true,
+		},
+		"declined sync command": {
+			statement:     "sync",
+			input:         "n\r",
+			workspacePath: createUniquePath("./tmp"),
+			want:          false,
+		},
+		"dry run sync command": {
+			statement:     "sync --dry-run",
+			input:         "y\r",
+			workspacePath: createUniquePath("./tmp"),
+			want:          false,
+		},
+		"error sync command": {
+			statement:     "sync --force",
+			input:         "y\r",
+			workspacePath: createUniquePath("./tmp"),
+			want:          false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			metadataFilePath := createUniquePath("./.notewolfy")
+			config := &structure.Config{
+				MetadataFilePath: metadataFilePath,
+			}
+			defer CleanUpFile(metadataFilePath)
+
+			mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+			assert.NoError(t, err)
+
+			// We need to prepare a workspace with a node and a markdown file that only exist on disk
+			workspacePath, err := utility.ExpandRelativePaths(tc.workspacePath)
+			assert.NoError(t, err)
+			err = os.MkdirAll(filepath.Join(workspacePath, "notes"), os.ModePerm)
+			assert.NoError(t, err)
+			defer os.RemoveAll(workspacePath)
+			err = os.WriteFile(filepath.Join(workspacePath, "notes", "example.md"), []byte("# example"), 0644)
+			assert.NoError(t, err)
+			workspaceNode := structure.NewNode("workspace", workspacePath)
+			mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
+			mmf.ActiveWorkspace = workspaceNode.Name
+			mmf.ActiveNode = workspaceNode.ID
+			err = mmf.Save()
+			assert.NoError(t, err)
+
+			tempFile, err := os.CreateTemp("", "tempStdin")
+			assert.NoError(t, err)
+			defer os.Remove(tempFile.Name())
+			_, err = tempFile.WriteString(tc.input)
+			assert.NoError(t, err)
+			_, err = tempFile.Seek(0, 0)
+			assert.NoError(t, err)
+			defer tempFile.Close()
+
+			oldStdin := os.Stdin
+			defer func() { os.Stdin = oldStdin }()
+			os.Stdin = tempFile
+
+			_, err = captureStdOutput(func() {
+				commands.MatchStatementToCommand(mmf, tc.statement)
+			})
+			assert.NoError(t, err)
+			if tc.want {
+				notesNode, err := mmf.ResolveNodePath("notes")
+				assert.NoError(t, err)
+				assert.Equal(t, "example.md", notesNode.Markdowns[0].Filename)
+
+				return
+			}
+			assert.Empty(t, mmf.Workspaces[0].Children)
+		})
+	}
+}
+
+func TestMatchStatementToAdoptWorkspace(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		statement     string
+		workspacePath string
+		want          bool
+	}{
+		"simple adopt workspace command": {
+			statement:     "adopt workspace adopted %s",
+			workspacePath: createUniquePath("./tmp"),
+			want:          true,
+		},
+		"error adopt workspace command with missing directory": {
+			statement:     "adopt workspace adopted %s/missing",
+			workspacePath: createUniquePath("./tmp"),
+			want:          false,
+		},
+		"error adopt workspace command that almost matches": {
+			statement:     "adopt workspaces adopted %s",
+			workspacePath: createUniquePath("./tmp"),
+			want:          false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			metadataFilePath := createUniquePath("./.notewolfy")
+			config := &structure.Config{
+				MetadataFilePath: metadataFilePath,
+			}
+			defer CleanUpFile(metadataFilePath)
+
+			mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+			assert.NoError(t, err)
+
+			// We need to prepare a directory full of notes
+			workspacePath, err := utility.ExpandRelativePaths(tc.workspacePath)
+			assert.NoError(t, err)
+			defer os.RemoveAll(workspacePath)
+			for _, directory := range []string{"research/papers", "drafts"} {
+				err = os.MkdirAll(filepath.Join(workspacePath, directory), os.ModePerm)
+				assert.NoError(t, err)
+			}
+			files := map[string]string{
+				"index.md":                 "# index",
+				"research/papers/paper.md": "# paper",
+				"research/todo.txt":        "todo",
+				"drafts/draft.md":          "# draft",
+				".notewolfyignore":         "drafts/\n",
+			}
+			for file, content := range files {
+				err = os.WriteFile(filepath.Join(workspacePath, file), []byte(content), 0644)
+				assert.NoError(t, err)
+			}
+
+			_, err = captureStdOutput(func() {
+				commands.MatchStatementToCommand(mmf, fmt.Sprintf(tc.statement, workspacePath))
+			})
+			assert.NoError(t, err)
+			// Adopting does not create anything inside of the directory
+			entries, err := os.ReadDir(workspacePath)
+			assert.NoError(t, err)
+			assert.Equal(t, 4, len(entries))
+			if tc.want {
+				assert.Equal(t, 1, len(mmf.Workspaces))
+				assert.Equal(t, "adopted", mmf.ActiveWorkspace)
+				assert.Equal(t, mmf.Workspaces[0].ID, mmf.ActiveNode)
+				assert.Equal(t, "index.md", mmf.Workspaces[0].Markdowns[0].Filename)
+				assert.Equal(t, 1, len(mmf.Workspaces[0].Children))
+				papersNode, err := mmf.ResolveNodePath("/research/papers")
+				assert.NoError(t, err)
+				assert.Equal(t, "paper.md", papersNode.Markdowns[0].Filename)
+				assert.Equal(t, filepath.Join(workspacePath, "research", "papers"), papersNode.Path)
+				assert.NoDirExists(t, filepath.Join(workspacePath, structure.WorkspaceMetadataDirName))
+
+				// The tree of the adopted workspace is kept in the metadata file instead
+				actMmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+				assert.NoError(t, err)
+				assert.Equal(t, mmf.Workspaces, actMmf.Workspaces)
+
+				return
+			}
+			assert.Empty(t, mmf.Workspaces)
+		})
+	}
+}
+
+func TestMatchStatementToOpenWorkspacePath(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePathA := createUniquePath("./.notewolfy")
+	defer CleanUpFile(metadataFilePathA)
+	metadataFilePathB := createUniquePath("./.notewolfy")
+	defer CleanUpFile(metadataFilePathB)
+	workspacePath, err := utility.ExpandRelativePaths(createUniquePath("./tmp"))
+	assert.NoError(t, err)
+	defer os.RemoveAll(workspacePath)
+
+	mmfA, err := structure.NewMetadataNoteWolfyFileHandle(&structure.Config{MetadataFilePath: metadataFilePathA})
+	assert.NoError(t, err)
+	commands.MatchStatementToCommand(mmfA, fmt.Sprintf("create workspace shared %s", workspacePath))
+	commands.MatchStatementToCommand(mmfA, "create node research")
+
+	mmfB, err := structure.NewMetadataNoteWolfyFileHandle(&structure.Config{MetadataFilePath: metadataFilePathB})
+	assert.NoError(t, err)
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmfB, fmt.Sprintf("open %s", workspacePath))
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("\n\rOpened workspace 'shared' at %s!", workspacePath), output)
+	assert.Equal(t, "shared", mmfB.ActiveWorkspace)
+	assert.Equal(t, mmfA.Workspaces[0].ID, mmfB.ActiveNode)
+	assert.Equal(t, "research", mmfB.Workspaces[0].Children[0].Name)
+}
+
+func TestMatchStatementToCreateWorkspaceWithDefaultRoot(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := createUniquePath("./.notewolfy")
+	defaultWorkspaceRoot := t.TempDir()
+	config := &structure.Config{
+		MetadataFilePath:     metadataFilePath,
+		DefaultWorkspaceRoot: defaultWorkspaceRoot,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+
+	commands.MatchStatementToCommand(mmf, "create workspace example")
+	exists, err := fileOrDirectoryExists(filepath.Join(defaultWorkspaceRoot, "example"))
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, filepath.Join(defaultWorkspaceRoot, "example"), mmf.Workspaces[0].Path)
+
+	mmf.Config.DefaultWorkspaceRoot = ""
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "create workspace another")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rPlease specify a workspace path or configure a default_workspace_root in your config file!\n", output)
+	assert.Equal(t, 1, len(mmf.Workspaces))
+}
+
+func TestMatchStatementToUndoAndRedo(t *testing.T) {
+	t.Parallel()
+
+	mmf, workspacePath := createTestWorkspace(t, &structure.Config{})
+	commands.MatchStatementToCommand(mmf, "create node research")
+	commands.MatchStatementToCommand(mmf, "create md research/notes")
+	markdownPath := filepath.Join(workspacePath, "research", "notes.md")
+	err := os.WriteFile(markdownPath, []byte("# important notes"), 0644)
+	assert.NoError(t, err)
+	commands.MatchStatementToCommand(mmf, "goto research")
+	_, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "delete md notes")
+	})
+	assert.NoError(t, err)
+	_, err = os.Stat(markdownPath)
+	assert.True(t, os.IsNotExist(err))
+
+	// Undoing the delete restores the file together with its content
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "undo")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rUndid 'delete md notes'!", output)
+	content, err := os.ReadFile(markdownPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# important notes", string(content))
+	researchNode, err := mmf.ResolveNodePath("/research")
+	assert.NoError(t, err)
+	assert.Equal(t, "notes.md", researchNode.Markdowns[0].Filename)
+
+	// Undoing the navigation and the creation of the markdown file
+	commands.MatchStatementToCommand(mmf, "undo")
+	assert.Equal(t, mmf.Workspaces[0].ID, mmf.ActiveNode)
+	commands.MatchStatementToCommand(mmf, "undo")
+	_, err = os.Stat(markdownPath)
+	assert.True(t, os.IsNotExist(err))
+	assert.Empty(t, mmf.Workspaces[0].Children[0].Markdowns)
+
+	// Redo brings back the markdown file with the content it had when it was undone
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "redo")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rRedid 'create md research/notes'!", output)
+	content, err = os.ReadFile(markdownPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# important notes", string(content))
+	assert.Equal(t, "notes.md", mmf.Workspaces[0].Children[0].Markdowns[0].Filename)
+
+	// A new command discards the commands that could have been redone
+	commands.MatchStatementToCommand(mmf, "create node drafts")
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "redo")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rCould not redo, there is nothing to redo!\n", output)
+}
+
+func TestMatchStatementToTrashCommands(t *testing.T) {
+	config := &structure.Config{
+		TrashMaxAge: time.Hour,
+	}
+	mmf, workspacePath := createTestWorkspace(t, config)
+	commands.MatchStatementToCommand(mmf, "create node research")
+	commands.MatchStatementToCommand(mmf, "create md research/notes")
+	markdownPath := filepath.Join(workspacePath, "research", "notes.md")
+	err := os.WriteFile(markdownPath, []byte("# important notes"), 0644)
+	assert.NoError(t, err)
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "ls trash")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rThe trash is empty!", output)
+
+	// Deleting the markdown file and its node moves both into the trash
+	commands.MatchStatementToCommand(mmf, "delete md research/notes")
+	_, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "delete node research")
+	})
+	assert.NoError(t, err)
+	assert.NoDirExists(t, filepath.Join(workspacePath, "research"))
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "ls trash")
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, output, "/research/notes.md")
+	assert.Contains(t, output, "/research")
+
+	// Restoring the markdown file recreates the research node
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "restore 1")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rRestored markdown 'notes.md'!", output)
+	content, err := os.ReadFile(markdownPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# important notes", string(content))
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "restore 5")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rThere is no trash item with the number 5, use 'ls trash' to list the trash!\n", output)
+
+	// Items younger than the configured age are kept
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "empty-trash")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rRemoved 0 items from the trash!", output)
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "empty-trash 0")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rRemoved 1 items from the trash!", output)
+	assert.Empty(t, mmf.ListTrash())
+
+	// The deletion of the purged node cannot be undone anymore, undo continues with the restore
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "undo")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rUndid 'restore 1'!", output)
+	assert.NoFileExists(t, markdownPath)
+	assert.Len(t, mmf.ListTrash(), 1)
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "undo")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rCould not undo, there is nothing to undo!\n", output)
+}
+
+func TestMatchStatementToRecursiveDelete(t *testing.T) {
+	mmf, workspacePath := createTestWorkspace(t, &structure.Config{})
+	commands.MatchStatementToCommand(mmf, "create node research")
+	commands.MatchStatementToCommand(mmf, "goto research")
+	commands.MatchStatementToCommand(mmf, "create node papers")
+	commands.MatchStatementToCommand(mmf, "create md papers/summary")
+	err := os.WriteFile(filepath.Join(workspacePath, "research", "papers", "summary.md"), []byte("# summary"), 0644)
+	assert.NoError(t, err)
+	commands.MatchStatementToCommand(mmf, "goto /")
+
+	answer := func(input string) func() {
+		tempFile, err := os.CreateTemp("", "tempStdin")
+		assert.NoError(t, err)
+		_, err = tempFile.WriteString(input)
+		assert.NoError(t, err)
+		_, err = tempFile.Seek(0, 0)
+		assert.NoError(t, err)
+		oldStdin := os.Stdin
+		os.Stdin = tempFile
+		return func() {
+			os.Stdin = oldStdin
+			tempFile.Close()
+			os.Remove(tempFile.Name())
+		}
+	}
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "delete node research")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Please delete all subsequent nodes and markdown files before deleting 'research' or use 'delete node -r research'!\n", output)
+
+	// Declining the confirmation keeps everything in place
+	restoreStdin := answer("n\n")
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "delete node -r research")
+	})
+	restoreStdin()
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rDeleting node 'research' removes it together with 1 nodes and 1 markdown files below it, 9 B on disk:\n\r+ papers\n\r  - summary.md\n\rDo you want to move all of it to the trash? [y/N] n\n\rDeletion aborted, nothing was deleted!", output)
+	assert.DirExists(t, filepath.Join(workspacePath, "research"))
+	assert.Len(t, mmf.Workspaces[0].Children, 1)
+
+	restoreStdin = answer("y\n")
+	_, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "delete node -r research")
+	})
+	restoreStdin()
+	assert.NoError(t, err)
+	assert.NoDirExists(t, filepath.Join(workspacePath, "research"))
+	assert.Empty(t, mmf.Workspaces[0].Children)
+	assert.Len(t, mmf.ListTrash(), 1)
+
+	// Undo brings back the whole subtree
+	commands.MatchStatementToCommand(mmf, "undo")
+	assert.FileExists(t, filepath.Join(workspacePath, "research", "papers", "summary.md"))
+	assert.Len(t, mmf.Workspaces[0].Children, 1)
+
+	restoreStdin = answer("y\n")
+	_, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "delete workspace --purge test")
+	})
+	restoreStdin()
+	assert.NoError(t, err)
+	assert.NoDirExists(t, workspacePath)
+	assert.Empty(t, mmf.Workspaces)
+	assert.Empty(t, mmf.ActiveWorkspace)
+	assert.Len(t, mmf.ListTrash(), 1)
+}
+
+func TestMatchStatementToRename(t *testing.T) {
+	mmf, workspacePath := createTestWorkspace(t, &structure.Config{})
+	parentPath := filepath.Dir(workspacePath)
+	commands.MatchStatementToCommand(mmf, "create node research")
+	commands.MatchStatementToCommand(mmf, "goto research")
+	commands.MatchStatementToCommand(mmf, "create node papers")
+	commands.MatchStatementToCommand(mmf, "create md papers/summary")
+	commands.MatchStatementToCommand(mmf, "create md draft")
+	activeNodeID := mmf.ActiveNode
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "rename md draft notes")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rRenamed markdown file 'draft' to 'notes'!", output)
+	assert.FileExists(t, filepath.Join(workspacePath, "research", "notes.md"))
+	assert.Equal(t, "notes.md", mmf.FindNode(activeNodeID).Markdowns[0].Filename)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "rename md notes papers/summary")
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Please use 'rename md <old> <new>'")
+
+	// Renaming the active node keeps it active and moves the whole subtree
+	_, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "rename node /research articles")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, activeNodeID, mmf.ActiveNode)
+	papersNode, err := mmf.ResolveNodePath("/articles/papers")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(workspacePath, "articles", "papers"), papersNode.Path)
+	assert.FileExists(t, filepath.Join(papersNode.Path, "summary.md"))
+
+	_, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "rename workspace test notes")
+	})
+	assert.NoError(t, err)
+	renamedPath := filepath.Join(parentPath, "notes")
+	assert.Equal(t, "notes", mmf.ActiveWorkspace)
+	assert.Equal(t, activeNodeID, mmf.ActiveNode)
+	assert.Equal(t, renamedPath, mmf.Workspaces[0].Path)
+	papersNode, err = mmf.ResolveNodePath("/articles/papers")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(renamedPath, "articles", "papers"), papersNode.Path)
+	assert.FileExists(t, filepath.Join(papersNode.Path, "summary.md"))
+
+	// Undo reverts the rename of the workspace directory
+	commands.MatchStatementToCommand(mmf, "undo")
+	assert.Equal(t, "test", mmf.ActiveWorkspace)
+	assert.DirExists(t, filepath.Join(workspacePath, "articles", "papers"))
+	assert.NoDirExists(t, renamedPath)
+}
+
+func TestMatchStatementToMove(t *testing.T) {
+	metadataFilePath := createUniquePath("./.notewolfy")
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+	workspacePath := filepath.Join(t.TempDir(), "test")
+	otherWorkspacePath := filepath.Join(t.TempDir(), "other")
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	commands.MatchStatementToCommand(mmf, fmt.Sprintf("create workspace other %s", otherWorkspacePath))
+	commands.MatchStatementToCommand(mmf, fmt.Sprintf("create workspace test %s", workspacePath))
+	commands.MatchStatementToCommand(mmf, "create node research")
+	commands.MatchStatementToCommand(mmf, "create node archive")
+	commands.MatchStatementToCommand(mmf, "goto research")
+	commands.MatchStatementToCommand(mmf, "create node papers")
+	commands.MatchStatementToCommand(mmf, "create md papers/summary")
+	commands.MatchStatementToCommand(mmf, "create md draft")
+	commands.MatchStatementToCommand(mmf, "goto /")
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "mv md research/draft archive")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rMoved markdown file 'draft' to 'archive'!", output)
+	assert.FileExists(t, filepath.Join(workspacePath, "archive", "draft.md"))
+	archiveNode, err := mmf.ResolveNodePath("/archive")
+	assert.NoError(t, err)
+	assert.Equal(t, "draft.md", archiveNode.Markdowns[0].Filename)
+
+	// A node cannot be moved below itself
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "mv node research research/papers")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rCould not move 'research', node 'research' cannot be moved into itself or one of its child nodes!\n", output)
+
+	_, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "mv node research archive")
+	})
+	assert.NoError(t, err)
+	papersNode, err := mmf.ResolveNodePath("/archive/research/papers")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(workspacePath, "archive", "research", "papers"), papersNode.Path)
+	assert.FileExists(t, filepath.Join(papersNode.Path, "summary.md"))
+
+	// Moving into another workspace
+	_, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "mv node archive/research other:/")
+	})
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(otherWorkspacePath, "research", "papers", "summary.md"))
+	assert.Len(t, mmf.Workspaces[1].Children[0].Children, 0)
+	assert.Equal(t, filepath.Join(otherWorkspacePath, "research", "papers"), mmf.Workspaces[0].Children[0].Children[0].Path)
+
+	commands.MatchStatementToCommand(mmf, "undo")
+	assert.FileExists(t, filepath.Join(papersNode.Path, "summary.md"))
+	assert.Empty(t, mmf.Workspaces[0].Children)
+}
+
+func TestMatchStatementToCopy(t *testing.T) {
+	mmf, workspacePath := createTestWorkspace(t, &structure.Config{})
+	commands.MatchStatementToCommand(mmf, "create node template")
+	commands.MatchStatementToCommand(mmf, "goto template")
+	commands.MatchStatementToCommand(mmf, "create node tasks")
+	commands.MatchStatementToCommand(mmf, "goto /")
+	commands.MatchStatementToCommand(mmf, "create node archive")
+	commands.MatchStatementToCommand(mmf, "create md template/plan")
+	planPath := filepath.Join(workspacePath, "template", "plan.md")
+	err := os.WriteFile(planPath, []byte("---\ntags: [work]\n---\n# plan"), 0644)
+	assert.NoError(t, err)
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "cp md template/plan template/plan")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rCopied markdown file 'plan' to 'plan_copy.md' on node 'template'!", output)
+	content, err := os.ReadFile(filepath.Join(workspacePath, "template", "plan_copy.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "---\ntags: [work]\n---\n# plan", string(content))
+
+	// The copy has its own metadata, is indexed and found by the tag listing
+	copiedMarkdown := mmf.FindMarkdown(mmf.Workspaces[0].Children[0], "plan_copy")
+	if assert.NotNil(t, copiedMarkdown) {
+		assert.Equal(t, "plan", copiedMarkdown.Title)
+		assert.Equal(t, []string{"work"}, copiedMarkdown.Tags)
+		assert.Equal(t, 2, copiedMarkdown.Words)
+		assert.False(t, copiedMarkdown.Created.IsZero())
+	}
+	index, err := mmf.LoadIndex()
+	assert.NoError(t, err)
+	assert.Contains(t, index.Documents, copiedMarkdown.ID)
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "ls tag:work")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rNotes tagged with 'work':\n\r /template\tplan_copy.md", output)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "cp md template/plan archive")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rCopied markdown file 'plan' to 'plan.md' on node 'archive'!", output)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "cp node template /")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rPlease use 'cp node -r <nodePath> <targetNodePath>' to copy a node with all of its content!\n", output)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "cp node -r template template/tasks")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rCould not copy 'template', node 'template' cannot be copied into itself or one of its child nodes!\n", output)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "cp node -r template /")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rCopied node 'template' to 'template_copy' below node 'test'!", output)
+	templateNode, err := mmf.ResolveNodePath("/template")
+	assert.NoError(t, err)
+	copiedNode, err := mmf.ResolveNodePath("/template_copy")
+	assert.NoError(t, err)
+	assert.NotEqual(t, templateNode.ID, copiedNode.ID)
+	assert.NotEqual(t, templateNode.Markdowns[0].ID, copiedNode.Markdowns[0].ID)
+	assert.Equal(t, filepath.Join(workspacePath, "template_copy", "tasks"), copiedNode.Children[0].Path)
+	assert.FileExists(t, filepath.Join(workspacePath, "template_copy", "plan_copy.md"))
+	assert.Equal(t, []string{"work"}, copiedNode.Markdowns[0].Tags)
+
+	// Undo removes the copy from disk again
+	commands.MatchStatementToCommand(mmf, "undo")
+	assert.NoDirExists(t, filepath.Join(workspacePath, "template_copy"))
+	_, err = mmf.ResolveNodePath("/template_copy")
+	assert.Error(t, err)
+}
+
+func TestMatchStatementToListInDetail(t *testing.T) {
+	mmf, workspacePath := createTestWorkspace(t, &structure.Config{})
+	commands.MatchStatementToCommand(mmf, "create md groceries")
+	markdown := mmf.FindMarkdown(mmf.Workspaces[0], "groceries")
+	if assert.NotNil(t, markdown) {
+		assert.Equal(t, "groceries", markdown.Title)
+		assert.False(t, markdown.Created.IsZero())
+		assert.Equal(t, 0, markdown.Words)
+	}
+
+	// Notes edited outside of notewolfy are picked up by sync without asking for confirmation
+	content := "---\ntitle: Groceries\ntags: [home]\n---\nmilk and eggs\n"
+	err := os.WriteFile(filepath.Join(workspacePath, "groceries.md"), []byte(content), 0644)
+	assert.NoError(t, err)
+	commands.MatchStatementToCommand(mmf, "sync")
+	markdown = mmf.FindMarkdown(mmf.Workspaces[0], "groceries")
+	if assert.NotNil(t, markdown) {
+		assert.Equal(t, "Groceries", markdown.Title)
+		assert.Equal(t, 3, markdown.Words)
+		assert.Equal(t, []string{"home"}, markdown.Tags)
+	}
+
+	actOutput, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "ls -l")
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, actOutput, "\r groceries.md\t\"Groceries\"\t")
+	assert.Contains(t, actOutput, fmt.Sprintf("\t%d B\t3 words\t[home]\n", len(content)))
+
+	actOutput, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "ls")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\r\nYou are on node:  test\n\rChild nodes:\n\rMarkdown files:\n\r groceries.md\n", actOutput)
+}
+
+func TestMatchStatementToTags(t *testing.T) {
+	mmf, workspacePath := createTestWorkspace(t, &structure.Config{})
+	commands.MatchStatementToCommand(mmf, "create node meetings")
+	commands.MatchStatementToCommand(mmf, "create md meetings/weekly")
+	commands.MatchStatementToCommand(mmf, "create md groceries")
+	groceriesPath := filepath.Join(workspacePath, "groceries.md")
+	err := os.WriteFile(groceriesPath, []byte("---\ntitle: Groceries\ntags: [home]\n---\nmilk\n"), 0644)
+	assert.NoError(t, err)
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "tag md meetings/weekly work #Todo")
+		commands.MatchStatementToCommand(mmf, "tag md groceries todo")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rTags of markdown file 'weekly': [todo, work]\n\rTags of markdown file 'groceries': [home, todo]", output)
+	content, err := os.ReadFile(groceriesPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "---\ntitle: Groceries\ntags: [home, todo]\n---\nmilk\n", string(content))
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "tags")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rhome (1)\n\rtodo (2)\n\rwork (1)", output)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "ls tag:todo")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rNotes tagged with 'todo':\n\r /\tgroceries.md\n\r /meetings\tweekly.md", output)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "untag md meetings/weekly todo work")
+		commands.MatchStatementToCommand(mmf, "ls tag:work")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rTags of markdown file 'weekly': []\n\rNo note of the active workspace is tagged with 'work'!", output)
+	content, err = os.ReadFile(filepath.Join(workspacePath, "meetings", "weekly.md"))
+	assert.NoError(t, err)
+	assert.Empty(t, content)
+
+	// Undo restores the front matter of the note as well
+	commands.MatchStatementToCommand(mmf, "undo")
+	content, err = os.ReadFile(filepath.Join(workspacePath, "meetings", "weekly.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "---\ntags: [todo, work]\n---\n", string(content))
+	assert.Equal(t, []string{"todo", "work"}, mmf.FindMarkdown(mmf.FindNode(mmf.Workspaces[0].Children[0].ID), "weekly").Tags)
+}
+
+func TestMatchStatementToSearch(t *testing.T) {
+	// The editor appends a line, so that we can see which note has been opened
+	editorPath := filepath.Join(t.TempDir(), "editor.sh")
+	err := os.WriteFile(editorPath, []byte("#!/bin/sh\necho edited >> \"$1\"\n"), 0755)
+	assert.NoError(t, err)
+	config := &structure.Config{
+		Editor: editorPath,
+	}
+	mmf, workspacePath := createTestWorkspace(t, config)
+	commands.MatchStatementToCommand(mmf, "create node meetings")
+	commands.MatchStatementToCommand(mmf, "create md meetings/weekly")
+	commands.MatchStatementToCommand(mmf, "create md groceries")
+	weeklyPath := filepath.Join(workspacePath, "meetings", "weekly.md")
+	err = os.WriteFile(weeklyPath, []byte("# Weekly\nbuy a present\n"), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(workspacePath, "groceries.md"), []byte("Buy milk\n"), 0644)
+	assert.NoError(t, err)
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "search buy")
+	})
+	assert.NoError(t, err)
+	expOutput := "\n\r[1] / groceries.md:1: \033[1;31mBuy\033[0m milk\n\r[2] /meetings weekly.md:2: \033[1;31mbuy\033[0m a present\n\rUse 'edit #<n>' to open a result."
+	assert.Equal(t, expOutput, output)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "search --all present")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\r[1] test:/meetings weekly.md:2: buy a \033[1;31mpresent\033[0m\n\rUse 'edit #<n>' to open a result.", output)
+
+	commands.MatchStatementToCommand(mmf, "edit #1")
+	content, err := os.ReadFile(weeklyPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Weekly\nbuy a present\nedited\n", string(content))
+	assert.Equal(t, 6, mmf.FindMarkdown(mmf.Workspaces[0].Children[0], "weekly").Words)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "edit #2")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rCould not open search result #2, there is no search result #2!\n", output)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "search nothing")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rNo note contains 'nothing'!", output)
+}
+
+func TestMatchStatementToReindex(t *testing.T) {
+	mmf, workspacePath := createTestWorkspace(t, &structure.Config{})
+	commands.MatchStatementToCommand(mmf, "create md weekly")
+	commands.MatchStatementToCommand(mmf, "create md groceries")
+	weekly := mmf.FindMarkdown(mmf.Workspaces[0], "weekly")
+	index, err := mmf.LoadIndex()
+	assert.NoError(t, err)
+	assert.Len(t, index.Documents, 2)
+	assert.Contains(t, index.Documents, weekly.ID)
+
+	commands.MatchStatementToCommand(mmf, "rename md weekly monday")
+	index, err = mmf.LoadIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(workspacePath, "monday.md"), index.Documents[weekly.ID].Path)
+
+	commands.MatchStatementToCommand(mmf, "delete md monday")
+	index, err = mmf.LoadIndex()
+	assert.NoError(t, err)
+	assert.Len(t, index.Documents, 1)
+	assert.NotContains(t, index.Documents, weekly.ID)
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "undo")
+		commands.MatchStatementToCommand(mmf, "reindex")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rUndid 'delete md monday'!\n\rIndexed 2 notes!", output)
+}
+
+func TestMatchStatementToFind(t *testing.T) {
+	editorPath := filepath.Join(t.TempDir(), "editor.sh")
+	err := os.WriteFile(editorPath, []byte("#!/bin/sh\necho edited >> \"$1\"\n"), 0755)
+	assert.NoError(t, err)
+	config := &structure.Config{
+		Editor: editorPath,
+	}
+	mmf, workspacePath := createTestWorkspace(t, config)
+	commands.MatchStatementToCommand(mmf, "create node meetings")
+	commands.MatchStatementToCommand(mmf, "create md meetings/weekly")
+	commands.MatchStatementToCommand(mmf, "create md groceries")
+
+	stdinFile := filepath.Join(t.TempDir(), "stdin")
+	err = os.WriteFile(stdinFile, []byte("1\r"), 0644)
+	assert.NoError(t, err)
+	tempStdin, err := os.Open(stdinFile)
+	assert.NoError(t, err)
+	defer tempStdin.Close()
+	oldStdin := os.Stdin
+	defer func() { os.Stdin = oldStdin }()
+	os.Stdin = tempStdin
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "find wkly")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\r[1] /meetings/weekly.md\n\rWhich note do you want to open? [1-1] 1", output)
+	assert.Equal(t, mmf.Workspaces[0].Children[0].ID, mmf.ActiveNode)
+	content, err := os.ReadFile(filepath.Join(workspacePath, "meetings", "weekly.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "edited\n", string(content))
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "find xyz")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rNo note matches 'xyz'!", output)
+
+	// Going back to the previous node works like after goto
+	commands.MatchStatementToCommand(mmf, "undo")
+	assert.Equal(t, mmf.Workspaces[0].ID, mmf.ActiveNode)
+}
+
+func TestMatchStatementToLinksAndBacklinks(t *testing.T) {
+	mmf, workspacePath := createTestWorkspace(t, &structure.Config{})
+	commands.MatchStatementToCommand(mmf, "create node meetings")
+	commands.MatchStatementToCommand(mmf, "create md meetings/weekly")
+	commands.MatchStatementToCommand(mmf, "create md meetings/monthly")
+	commands.MatchStatementToCommand(mmf, "create md groceries")
+	err := os.WriteFile(filepath.Join(workspacePath, "meetings", "weekly.md"), []byte("Prepare [[monthly]], buy [[/groceries|food]] and [[daily]]\n"), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(workspacePath, "groceries.md"), []byte("For [[meetings/monthly#snacks]]\n"), 0644)
+	assert.NoError(t, err)
+	commands.MatchStatementToCommand(mmf, "sync")
+	assert.Equal(t, []string{"monthly", "/groceries", "daily"}, mmf.FindMarkdown(mmf.Workspaces[0].Children[0], "weekly").Links)
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "links meetings/weekly")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rLinks of markdown file 'weekly.md':\n\r [[monthly]] -> /meetings/monthly.md\n\r [[/groceries]] -> /groceries.md\n\r [[daily]] -> could not be found", output)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "backlinks meetings/monthly")
+		commands.MatchStatementToCommand(mmf, "backlinks meetings/weekly")
+		commands.MatchStatementToCommand(mmf, "links groceries")
+	})
+	assert.NoError(t, err)
+	expOutput := "\n\rNotes linking to markdown file 'monthly.md':\n\r /groceries.md\n\r /meetings/weekly.md" +
+		"\n\rNo note links to markdown file 'weekly.md'!" +
+		"\n\rLinks of markdown file 'groceries.md':\n\r [[meetings/monthly]] -> /meetings/monthly.md"
+	assert.Equal(t, expOutput, output)
+}
+
+func TestMatchStatementToCheckLinks(t *testing.T) {
+	mmf, workspacePath := createTestWorkspace(t, &structure.Config{})
+	commands.MatchStatementToCommand(mmf, "create node meetings")
+	commands.MatchStatementToCommand(mmf, "create md meetings/weekly")
+	commands.MatchStatementToCommand(mmf, "create md groceries")
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "check links")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rAll links are valid!", output)
+
+	err = os.WriteFile(filepath.Join(workspacePath, "meetings", "weekly.md"), []byte("# Weekly\nBuy [food](../grocery.md) for [[groceries#snacks]]\n"), 0644)
+	assert.NoError(t, err)
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "check links")
+		commands.MatchStatementToCommand(mmf, "check links --fix")
+	})
+	assert.NoError(t, err)
+	expOutput := "\n\r/meetings/weekly.md:2: [food](../grocery.md) points to a file that does not exist, did you mean '../groceries.md'?" +
+		"\n\r/meetings/weekly.md:2: [[groceries#snacks]] points to a heading that does not exist" +
+		"\n\rFound 2 broken links!" +
+		"\n\rPlease use 'check links'!\n"
+	assert.Equal(t, expOutput, output)
+}
+
+func TestMatchStatementToExportGraph(t *testing.T) {
+	mmf, workspacePath := createTestWorkspace(t, &structure.Config{})
+	commands.MatchStatementToCommand(mmf, "create node meetings")
+	commands.MatchStatementToCommand(mmf, "create md meetings/weekly")
+	commands.MatchStatementToCommand(mmf, "create md groceries")
+	err := os.WriteFile(filepath.Join(workspacePath, "groceries.md"), []byte("For [[weekly]]\n"), 0644)
+	assert.NoError(t, err)
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "export graph --format mermaid --links")
+		commands.MatchStatementToCommand(mmf, "export graph --format svg")
+	})
+	assert.NoError(t, err)
+	expOutput := "\n\rgraph LR\n\r  n0[\"test\"]\n\r  n1(\"groceries.md\")\n\r  n2[\"meetings\"]\n\r  n3(\"weekly.md\")" +
+		"\n\r  n0 --> n1\n\r  n0 --> n2\n\r  n2 --> n3\n\r  n1 -.-> n3" +
+		"\n\rPlease use 'export graph --format dot|mermaid [--links] [--output <file>]'!\n"
+	assert.Equal(t, expOutput, output)
+
+	// The output path is expanded like every other path
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	outputPath := filepath.Join(homeDir, "test.dot")
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "export graph --format dot --output ~/test.dot")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("\n\rExported the graph of the active workspace to '%s'!", outputPath), output)
+	content, err := os.ReadFile(outputPath)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "digraph \"test\" {\n"))
+	assert.Contains(t, string(content), "  n2 -> n3;\n")
+}
+
+func TestMatchStatementToTree(t *testing.T) {
+	mmf, _ := createTestWorkspace(t, &structure.Config{})
+	commands.MatchStatementToCommand(mmf, "create node meetings")
+	commands.MatchStatementToCommand(mmf, "create node projects")
+	commands.MatchStatementToCommand(mmf, "create md groceries")
+	commands.MatchStatementToCommand(mmf, "create md meetings/weekly")
+	commands.MatchStatementToCommand(mmf, "goto meetings")
+	commands.MatchStatementToCommand(mmf, "create node archive")
+	commands.MatchStatementToCommand(mmf, "create md archive/old")
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "tree")
+	})
+	assert.NoError(t, err)
+	expOutput := "\n\rtest (3 notes)" +
+		"\n\r├── \033[1;31mmeetings (2 notes)\033[0m" +
+		"\n\r│   └── archive (1 note)" +
+		"\n\r└── projects (0 notes)"
+	assert.Equal(t, expOutput, output)
+
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "tree --notes -L 1 /")
+		commands.MatchStatementToCommand(mmf, "tree --notes archive")
+		commands.MatchStatementToCommand(mmf, "tree -L 0")
+		commands.MatchStatementToCommand(mmf, "tree archive -L")
+		commands.MatchStatementToCommand(mmf, "tree archive /")
+	})
+	assert.NoError(t, err)
+	expOutput = "\n\rtest (3 notes)" +
+		"\n\r├── \033[1;31mmeetings (2 notes) [1 node collapsed]\033[0m" +
+		"\n\r├── projects (0 notes)" +
+		"\n\r└── groceries.md" +
+		"\n\rarchive (1 note)" +
+		"\n\r└── old.md" +
+		"\n\rThe depth '0' has to be a number greater than 0!\n" +
+		"\n\rPlease use 'tree [<nodePath>] [-L <depth>] [--notes]'!\n" +
+		"\n\rPlease use 'tree [<nodePath>] [-L <depth>] [--notes]'!\n"
+	assert.Equal(t, expOutput, output)
+}
+
+func TestMatchStatementToEditWithConfiguredEditor(t *testing.T) {
+	// The editor appends its first argument to the note, so that we can see how it has been called
+	editorPath := filepath.Join(t.TempDir(), "editor.sh")
+	err := os.WriteFile(editorPath, []byte("#!/bin/sh\necho \"$1\" >> \"$2\"\n"), 0755)
+	assert.NoError(t, err)
+	config := &structure.Config{
+		Editor: fmt.Sprintf("'%s' --wait", editorPath),
+	}
+	mmf, workspacePath := createTestWorkspace(t, config)
+	commands.MatchStatementToCommand(mmf, "create md weekly")
+	weeklyPath := filepath.Join(workspacePath, "weekly.md")
+
+	commands.MatchStatementToCommand(mmf, "edit weekly")
+	content, err := os.ReadFile(weeklyPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "--wait\n", string(content))
+
+	// The editor of the extension wins over the default editor
+	config.Editors = map[string]string{".md": fmt.Sprintf("%s --markdown", editorPath)}
+	commands.MatchStatementToCommand(mmf, "edit weekly")
+	content, err = os.ReadFile(weeklyPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "--wait\n--markdown\n", string(content))
+
+	config.Editors = map[string]string{".md": "notewolfy-missing-editor --wait"}
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "edit weekly")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\rThe editor 'notewolfy-missing-editor' could not be found! Install it or configure another one with 'editor' in the config file, $VISUAL or $EDITOR.\n", output)
+
+	config.Editors = map[string]string{".md": fmt.Sprintf("'%s", editorPath)}
+	output, err = captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "edit weekly")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("\n\rPlease check the configured editor, command ''%s' has an unterminated quote or escape!\n", editorPath), output)
+}
+
+func TestMatchStatementToEditReleasesTheLock(t *testing.T) {
+	if _, err := exec.LookPath("flock"); err != nil {
+		t.Skip("flock is not installed")
+	}
+	metadataFilePath, err := utility.ExpandRelativePaths(createUniquePath("./.notewolfy"))
+	assert.NoError(t, err)
+	// The editor notes whether it could take the lock of the metadata file while it was running
+	editorPath := filepath.Join(t.TempDir(), "editor.sh")
+	script := fmt.Sprintf("#!/bin/sh\nif flock -n '%s.lock' true; then echo unlocked >> \"$1\"; else echo locked >> \"$1\"; fi\n", metadataFilePath)
+	err = os.WriteFile(editorPath, []byte(script), 0755)
+	assert.NoError(t, err)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+		Editor:           editorPath,
+	}
+	mmf, workspacePath := createTestWorkspace(t, config)
+	commands.MatchStatementToCommand(mmf, "create md weekly")
+
+	commands.MatchStatementToCommand(mmf, "edit weekly")
+	content, err := os.ReadFile(filepath.Join(workspacePath, "weekly.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "unlocked\n", string(content))
+	assert.Equal(t, 1, mmf.Workspaces[0].Markdowns[0].Words)
+}
+
+func TestMatchStatementToCreateNodeWhoseDirectoryCannotBeCreated(t *testing.T) {
+	mmf, workspacePath := createTestWorkspace(t, &structure.Config{})
+	err := os.WriteFile(filepath.Join(workspacePath, "research"), []byte("not a directory"), 0644)
+	assert.NoError(t, err)
+
+	output, err := captureStdOutput(func() {
+		commands.MatchStatementToCommand(mmf, "create node research")
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, output, "file exists")
+	assert.Empty(t, mmf.Workspaces[0].Children)
+	_, err = mmf.Undo()
+	assert.Error(t, err)
+}
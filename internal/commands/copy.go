@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+)
+
+// parseCopyStatement returns the source path and the target path of a cp statement.
+func parseCopyStatement(statement string, resource string) (string, string, error) {
+	sourceCaptureGroupName := "source"
+	targetCaptureGroupName := "target"
+	pattern := fmt.Sprintf("^cp %s (?P<%s>%s) (?P<%s>%s)$", resource, sourceCaptureGroupName, nodePathPattern, targetCaptureGroupName, targetNodePathPattern)
+	copyRegex := regexp.MustCompile(pattern)
+	matches := copyRegex.FindStringSubmatch(statement)
+	if len(matches) != 3 {
+		return "", "", fmt.Errorf("\n\rPlease use 'cp %s <path> <targetPath>', where the path matches the regex %s and the target path matches the regex %s!", resource, nodePathPattern, targetNodePathPattern)
+	}
+	names := copyRegex.SubexpNames()
+	var sourcePath string
+	var targetPath string
+	for i, name := range names[1:] {
+		if name == sourceCaptureGroupName {
+			sourcePath = matches[i+1]
+		} else if name == targetCaptureGroupName {
+			targetPath = matches[i+1]
+		}
+	}
+
+	return sourcePath, targetPath, nil
+}
+
+type CopyMarkdownStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (cms *CopyMarkdownStrategy) Run() error {
+	markdownPath, targetPath, err := parseCopyStatement(cms.statement, "md")
+	if err != nil {
+		return err
+	}
+	node, markdownName, err := resolveNamedPath(cms.mmf, markdownPath)
+	if err != nil {
+		return err
+	}
+	markdown := cms.mmf.FindMarkdown(node, markdownName)
+	if markdown == nil {
+		return fmt.Errorf("\n\rMarkdown file '%s' could not be found on node '%s'!", markdownName, node.Name)
+	}
+
+	// The target is either a node, which keeps the name of the markdown file, or a node path followed by a new name.
+	newName := markdownName
+	_, targetNode, err := cms.mmf.ResolveWorkspaceNodePath(targetPath)
+	if err != nil {
+		workspacePrefix, nodePath := "", targetPath
+		if index := strings.Index(targetPath, structure.WorkspacePathSeparator); index != -1 {
+			workspacePrefix, nodePath = targetPath[:index+1], targetPath[index+1:]
+		}
+		nodePath, newName = structure.SplitNodePath(nodePath)
+		if !nameRegex.MatchString(newName) {
+			return fmt.Errorf("\n\rPlease check whether the name '%s' matches the regex %s!", newName, namePattern)
+		}
+		targetNode, err = resolveTargetNode(cms.mmf, workspacePrefix+nodePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	entry := cms.mmf.BeginJournalEntry(cms.statement)
+	copiedMarkdown, err := cms.mmf.CopyMarkdown(entry, node, markdown, targetNode, newName)
+	if err != nil {
+		return fmt.Errorf("\n\rCould not copy '%s', %v!", markdownName, err)
+	}
+	if err := cms.mmf.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rCopied markdown file '%s' to '%s' on node '%s'!", markdownName, copiedMarkdown.Filename, targetNode.Name)
+	if err := cms.mmf.CommitJournalEntry(entry); err != nil {
+		return err
+	}
+
+	return indexError(cms.mmf.IndexMarkdown(targetNode, copiedMarkdown))
+}
+
+type CopyNodeStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (cns *CopyNodeStrategy) Run() error {
+	if !strings.HasPrefix(cns.statement, "cp node -r ") {
+		return fmt.Errorf("\n\rPlease use 'cp node -r <nodePath> <targetNodePath>' to copy a node with all of its content!")
+	}
+	nodePath, targetPath, err := parseCopyStatement(cns.statement, "node -r")
+	if err != nil {
+		return err
+	}
+	node, err := resolveNode(cns.mmf, nodePath)
+	if err != nil {
+		return err
+	}
+	targetNode, err := resolveTargetNode(cns.mmf, targetPath)
+	if err != nil {
+		return err
+	}
+
+	entry := cns.mmf.BeginJournalEntry(cns.statement)
+	copiedNode, err := cns.mmf.CopyNode(entry, node, targetNode)
+	if err != nil {
+		return fmt.Errorf("\n\rCould not copy '%s', %v!", node.Name, err)
+	}
+	if err := cns.mmf.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rCopied node '%s' to '%s' below node '%s'!", node.Name, copiedNode.Name, targetNode.Name)
+	if err := cns.mmf.CommitJournalEntry(entry); err != nil {
+		return err
+	}
+
+	return indexError(cns.mmf.IndexNode(copiedNode))
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/RaphSku/notewolfy/internal/utility"
+)
+
+type ExportGraphStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (egs *ExportGraphStrategy) Run() error {
+	formatCaptureGroupName := "format"
+	linksCaptureGroupName := "links"
+	outputCaptureGroupName := "output"
+	pattern := fmt.Sprintf("^export graph --format (?P<%s>%s|%s)(?P<%s> --links)?(?: --output (?P<%s>\\S+))?$",
+		formatCaptureGroupName, structure.GraphFormatDot, structure.GraphFormatMermaid, linksCaptureGroupName, outputCaptureGroupName)
+	exportRegex := regexp.MustCompile(pattern)
+	matches := exportRegex.FindStringSubmatch(egs.statement)
+	if len(matches) != 4 {
+		return fmt.Errorf("\n\rPlease use 'export graph --format dot|mermaid [--links] [--output <file>]'!")
+	}
+	names := exportRegex.SubexpNames()
+	var format string
+	var withLinks bool
+	var outputPath string
+	for i, name := range names[1:] {
+		if name == formatCaptureGroupName {
+			format = matches[i+1]
+		} else if name == linksCaptureGroupName {
+			withLinks = matches[i+1] != ""
+		} else if name == outputCaptureGroupName {
+			outputPath = matches[i+1]
+		}
+	}
+
+	graph, err := ExportGraph(egs.mmf, format, withLinks, outputPath)
+	if err != nil {
+		return err
+	}
+	if outputPath == "" {
+		fmt.Print("\n\r" + strings.ReplaceAll(strings.TrimSuffix(graph, "\n"), "\n", "\n\r"))
+	}
+
+	return nil
+}
+
+// ExportGraph renders the graph of the active workspace and writes it to the output path, which may start with ~,
+// the graph is returned instead if no output path is given.
+func ExportGraph(mmf *structure.MetadataNoteWolfyFileHandle, format string, withLinks bool, outputPath string) (string, error) {
+	graph, err := mmf.ExportGraph(format, withLinks)
+	if err != nil {
+		return "", fmt.Errorf("\n\rCould not export the graph, %v!", err)
+	}
+	if outputPath == "" {
+		return graph, nil
+	}
+	expandedOutputPath, err := utility.ExpandRelativePaths(outputPath)
+	if err != nil {
+		return "", fmt.Errorf("\n\rCould not resolve the path '%s', %v!", outputPath, err)
+	}
+	if err := os.WriteFile(expandedOutputPath, []byte(graph), 0644); err != nil {
+		return "", fmt.Errorf("\n\rCould not write the graph to '%s', %v!", expandedOutputPath, err)
+	}
+	fmt.Printf("\n\rExported the graph of the active workspace to '%s'!", expandedOutputPath)
+
+	return "", nil
+}
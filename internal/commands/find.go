@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"time"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/RaphSku/notewolfy/internal/utility"
+)
+
+const maxFoundNotes = 10
+
+type FindStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (fs *FindStrategy) Run() error {
+	patternCaptureGroupName := "pattern"
+	pattern := fmt.Sprintf("^find (?P<%s>.+)$", patternCaptureGroupName)
+	findRegex := regexp.MustCompile(pattern)
+	matches := findRegex.FindStringSubmatch(fs.statement)
+	if len(matches) != 2 {
+		return fmt.Errorf("\n\rPlease use 'find <pattern>'!")
+	}
+	names := findRegex.SubexpNames()
+	var findPattern string
+	for i, name := range names[1:] {
+		if name == patternCaptureGroupName {
+			findPattern = matches[i+1]
+		}
+	}
+	if fs.mmf.FindActiveWorkspace() == nil {
+		return fmt.Errorf("\n\rSeems like you have not created a workspace yet! Create one with 'create workspace <workspace_name> <workspace_path>'")
+	}
+
+	foundNotes := fs.mmf.FindNotes(findPattern, time.Now())
+	if len(foundNotes) == 0 {
+		fmt.Printf("\n\rNo note matches '%s'!", findPattern)
+		return nil
+	}
+	foundNotes = foundNotes[:min(len(foundNotes), maxFoundNotes)]
+	for number, foundNote := range foundNotes {
+		fmt.Printf("\n\r[%d] %s", number+1, path.Join(foundNote.NodePath, foundNote.Markdown.Filename))
+	}
+	choice, err := utility.AskForChoice(os.Stdin, "Which note do you want to open?", len(foundNotes))
+	if err != nil {
+		return fmt.Errorf("\n\rCould not open a note, %v!", err)
+	}
+	if choice == 0 {
+		return nil
+	}
+	foundNote := foundNotes[choice-1]
+
+	entry := fs.mmf.BeginJournalEntry(fs.statement)
+	entry.TrackPosition()
+	fs.mmf.SetActiveNode(foundNote.Node.ID)
+	if err := fs.mmf.Save(); err != nil {
+		return err
+	}
+	if err := fs.mmf.CommitJournalEntry(entry); err != nil {
+		return err
+	}
+
+	return openInEditor(fs.mmf, foundNote.Node, foundNote.Markdown)
+}
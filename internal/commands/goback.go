@@ -9,11 +9,17 @@ type GoBackStrategy struct {
 }
 
 func (gbs *GoBackStrategy) Run() error {
-	activeNodeName := gbs.mmf.ActiveNode
-	parentNode := gbs.mmf.FindParentNode(activeNodeName)
+	activeNodeID := gbs.mmf.ActiveNode
+	parentNode := gbs.mmf.FindParentNode(activeNodeID)
 	if parentNode != nil {
-		gbs.mmf.ActiveNode = parentNode.Name
-		gbs.mmf.Save()
+		entry := gbs.mmf.BeginJournalEntry("goback")
+		entry.TrackPosition()
+		gbs.mmf.SetActiveNode(parentNode.ID)
+		if err := gbs.mmf.Save(); err != nil {
+			return err
+		}
+
+		return gbs.mmf.CommitJournalEntry(entry)
 	}
 
 	return nil
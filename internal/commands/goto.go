@@ -13,31 +13,39 @@ type GoToStrategy struct {
 }
 
 func (gts *GoToStrategy) Run() error {
-	nameCaptureGroupName := "name"
-	nodeNamePattern := "[\\w]+"
-	pattern := fmt.Sprintf("goto (?P<%s>%s)", nameCaptureGroupName, nodeNamePattern)
+	pathCaptureGroupName := "path"
+	pattern := fmt.Sprintf("goto (?P<%s>%s)", pathCaptureGroupName, nodePathPattern)
 	goToRegex := regexp.MustCompile(pattern)
 	matches := goToRegex.FindStringSubmatch(gts.statement)
 	if len(matches) != 2 {
-		return fmt.Errorf("\n\rPlease check whether the node matches the regex %s!", nodeNamePattern)
+		return fmt.Errorf("\n\rPlease check whether the node path matches the regex %s!", nodePathPattern)
 	}
 	names := goToRegex.SubexpNames()
-	var goToName string
+	var goToPath string
 	for i, name := range names[1:] {
-		if name == nameCaptureGroupName {
-			goToName = matches[i+1]
+		if name == pathCaptureGroupName {
+			goToPath = matches[i+1]
 		}
 	}
 
-	activeNodeName := gts.mmf.ActiveNode
-	activeNode := gts.mmf.FindNode(activeNodeName)
-	for _, childNode := range activeNode.Children {
-		if childNode.Name == goToName {
-			gts.mmf.ActiveNode = childNode.Name
-			gts.mmf.Save()
-			return nil
+	entry := gts.mmf.BeginJournalEntry(gts.statement)
+	entry.TrackPosition()
+	if goToPath == structure.PreviousNodePath {
+		previousNode := gts.mmf.FindNode(gts.mmf.PreviousNode)
+		if previousNode == nil {
+			return fmt.Errorf("\r\nThere is no previous node to go back to!")
 		}
+		gts.mmf.SetActiveNode(previousNode.ID)
+	} else {
+		node, err := resolveNode(gts.mmf, goToPath)
+		if err != nil {
+			return err
+		}
+		gts.mmf.SetActiveNode(node.ID)
+	}
+	if err := gts.mmf.Save(); err != nil {
+		return err
 	}
 
-	return fmt.Errorf("\r\nCould not find node '%s'!", goToName)
+	return gts.mmf.CommitJournalEntry(entry)
 }
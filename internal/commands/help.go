@@ -9,16 +9,41 @@ var (
 	commands = []string{
 		"ls",
 		"ls ws",
+		"ls trash",
+		"tree",
 		"create workspace",
 		"delete workspace",
+		"adopt workspace",
 		"create node",
 		"delete node",
 		"create md",
 		"delete md",
+		"rename md",
+		"rename node",
+		"rename workspace",
+		"mv md",
+		"mv node",
+		"cp md",
+		"cp node",
+		"tag md",
+		"untag md",
+		"tags",
+		"search",
+		"reindex",
+		"find",
+		"links",
+		"backlinks",
+		"check links",
+		"export graph",
 		"edit",
 		"goto",
 		"goback",
 		"open",
+		"undo",
+		"redo",
+		"restore",
+		"empty-trash",
+		"sync",
 		"version",
 	}
 )
@@ -28,7 +53,7 @@ type HelpStrategy struct {
 }
 
 func (hs *HelpStrategy) Run() error {
-	helpRegex := regexp.MustCompile("^help (?P<name>[[:alpha:]]+(?: [[:alpha:]]+)*)")
+	helpRegex := regexp.MustCompile("^help (?P<name>[[:alpha:]-]+(?: [[:alpha:]-]+)*)")
 	matches := helpRegex.FindStringSubmatch(hs.statement)
 	if len(matches) == 0 {
 		fmt.Print("\n\rYou need to specify a valid command, here is a list of possible commands:")
@@ -51,53 +76,153 @@ func (hs *HelpStrategy) Run() error {
 	var example string
 	switch helpCommand {
 	case "ls":
-		command = "\n\rCommand: ls"
-		description = "\n\rDescription: ls can be used to list information about the node that you are on, e.g. active node, markdown files on that node, etc."
-		example = "\n\rExample Usage: ls"
+		command = "\n\rCommand: ls [-l | tag:<tag>]"
+		description = "\n\rDescription: ls can be used to list information about the node that you are on, e.g. active node, markdown files on that node, etc. With -l the title, last modification, size, word count and tags of every markdown file are shown as well. With tag:<tag> the notes of all nodes of the active workspace that carry the tag are listed with their node paths."
+		example = "\n\rExample Usage: ls -l"
 	case "ls ws":
 		command = "\n\rCommand: ls ws"
 		description = "\n\rDescription: ls ws will list the workspaces and their root paths in a table format."
 		example = "\n\rExample Usage: ls ws"
+	case "ls trash":
+		command = "\n\rCommand: ls trash"
+		description = "\n\rDescription: ls trash lists the deleted markdown files and nodes of the active workspace as well as the deleted workspaces, together with their original location. Use the number of an item to restore it."
+		example = "\n\rExample Usage: ls trash"
+	case "tree":
+		command = "\n\rCommand: tree [<nodePath>] [-L <depth>] [--notes]"
+		description = "\n\rDescription: tree draws the hierarchy of the active workspace or of the node at the given path, every node is shown with the number of notes below it and the active node is highlighted. With -L only the given number of levels is drawn, deeper nodes are collapsed and counted on their parent. With --notes the markdown files are drawn as well."
+		example = "\n\rExample Usage: tree meetings -L 2 --notes"
 	case "create workspace":
-		command = "\n\rCommand: create workspace <workspaceName> <workspacePath>"
-		description = "\n\rDescription: create workspace will create a new workspace for you under the specified name and path that you can choose."
+		command = "\n\rCommand: create workspace <workspaceName> [<workspacePath>]"
+		description = "\n\rDescription: create workspace will create a new workspace for you under the specified name and path that you can choose. If you leave out the path, the workspace is created in the default_workspace_root of your config file."
 		example = "\n\rExample Usage: create workspace example /path/to/example"
 	case "delete workspace":
-		command = "\n\rCommand: delete workspace <workspaceName>"
-		description = "\n\rDescription: delete workspace lets you delete the specified workspace. This will fail if nodes & markdown files still exist on the node."
-		example = "\n\rExample Usage: delete workspace example"
+		command = "\n\rCommand: delete workspace [--purge] <workspaceName>"
+		description = "\n\rDescription: delete workspace lets you delete the specified workspace. This will fail if nodes & markdown files still exist on the node, unless you specify --purge, which deletes the workspace with all of its content after your confirmation. The workspace is moved into the trash, from where it can be restored."
+		example = "\n\rExample Usage: delete workspace --purge example"
+	case "adopt workspace":
+		command = "\n\rCommand: adopt workspace <workspaceName> <workspacePath>"
+		description = "\n\rDescription: adopt workspace registers an existing directory as a workspace. Subdirectories become nodes and .md files become markdown files, entries matching the .notewolfyignore file in the directory are skipped. Nothing on disk is moved or created, the structure of the workspace is kept in the metadata file of notewolfy."
+		example = "\n\rExample Usage: adopt workspace example ~/notes"
 	case "create node":
 		command = "\n\rCommand: create node <nodeName>"
 		description = "\n\rDescription: create node will create a new node for you under the specified name. The node path will correspond to /pathOfActiveNode/nodeName."
 		example = "\n\rExample Usage: create node example"
 	case "delete node":
-		command = "\n\rCommand: delete node <nodeName>"
-		description = "\n\rDescription: delete node lets you delete the specified node. This will fail if markdown files still exist on the node."
-		example = "\n\rExample Usage: delete node example"
+		command = "\n\rCommand: delete node [-r] <nodePath>"
+		description = "\n\rDescription: delete node lets you delete the node at the specified path, relative to the active node or absolute from the workspace root. This will fail if markdown files still exist on the node, unless you specify -r, which deletes the node with all of its child nodes and markdown files after your confirmation. The node is moved into the trash of the workspace, from where it can be restored."
+		example = "\n\rExample Usage: delete node -r research/example"
 	case "create md":
-		command = "\n\rCommand: create md <markdownFileName>"
-		description = "\n\rDescription: create md will create a new markdown file for you under the specified name. You don't need to append the file extension to the name."
-		example = "\n\rExample Usage: create md example"
+		command = "\n\rCommand: create md <markdownFilePath>"
+		description = "\n\rDescription: create md will create a new markdown file for you under the specified name. You don't need to append the file extension to the name. Prefix the name with a node path to create it on another node."
+		example = "\n\rExample Usage: create md research/example"
 	case "delete md":
-		command = "\n\rCommand: delete md <markdownFileName>"
-		description = "\n\rDescription: delete md lets you delete the specified markdown file. Specify only the name, so without the file extension."
-		example = "\n\rExample Usage: delete md example"
+		command = "\n\rCommand: delete md <markdownFilePath>"
+		description = "\n\rDescription: delete md lets you delete the specified markdown file. Specify only the name, so without the file extension, optionally prefixed with a node path. The markdown file is moved into the trash of the workspace, from where it can be restored."
+		example = "\n\rExample Usage: delete md research/example"
+	case "rename md":
+		command = "\n\rCommand: rename md <markdownFilePath> <newName>"
+		description = "\n\rDescription: rename md renames the markdown file on disk and in the metadata. Specify the names without the file extension, the old name can be prefixed with a node path."
+		example = "\n\rExample Usage: rename md research/example summary"
+	case "rename node":
+		command = "\n\rCommand: rename node <nodePath> <newName>"
+		description = "\n\rDescription: rename node renames the node at the specified path on disk and in the metadata, the paths of all child nodes are updated as well."
+		example = "\n\rExample Usage: rename node research/papers articles"
+	case "rename workspace":
+		command = "\n\rCommand: rename workspace <workspaceName> <newName>"
+		description = "\n\rDescription: rename workspace renames the workspace. If the directory of the workspace carries the workspace name, the directory is renamed as well."
+		example = "\n\rExample Usage: rename workspace example notes"
+	case "mv md":
+		command = "\n\rCommand: mv md <markdownFilePath> <targetNodePath>"
+		description = "\n\rDescription: mv md moves the markdown file to the target node on disk and in the metadata. Prefix the target node path with a workspace name and ':' to move the markdown file into another workspace, the path is then resolved from the root of that workspace."
+		example = "\n\rExample Usage: mv md research/example archive/2026 or mv md example other:/inbox"
+	case "mv node":
+		command = "\n\rCommand: mv node <nodePath> <targetNodePath>"
+		description = "\n\rDescription: mv node moves the node with all of its child nodes and markdown files below the target node. A node cannot be moved below itself. Prefix the target node path with a workspace name and ':' to move the node into another workspace."
+		example = "\n\rExample Usage: mv node research/papers /archive or mv node papers other:/"
+	case "cp md":
+		command = "\n\rCommand: cp md <markdownFilePath> <targetPath>"
+		description = "\n\rDescription: cp md copies the markdown file. If the target path is a node, the copy keeps the name of the markdown file, otherwise the last segment of the target path is the name of the copy. Existing files are never overwritten, the copy gets a _copy suffix instead. Prefix the target path with a workspace name and ':' to copy into another workspace."
+		example = "\n\rExample Usage: cp md meetings/weekly meetings/weekly_2026 or cp md weekly archive"
+	case "cp node":
+		command = "\n\rCommand: cp node -r <nodePath> <targetNodePath>"
+		description = "\n\rDescription: cp node copies the node with all of its child nodes and markdown files below the target node. If the target node already contains a node with the same name, the copy gets a _copy suffix. Prefix the target node path with a workspace name and ':' to copy into another workspace."
+		example = "\n\rExample Usage: cp node -r projects/template projects"
+	case "tag md":
+		command = "\n\rCommand: tag md <markdownFilePath> <tag...>"
+		description = "\n\rDescription: tag md adds the tags to the 'tags' entry of the YAML front matter of the markdown file and to the metadata. Tags are stored in lowercase without a leading '#'."
+		example = "\n\rExample Usage: tag md meetings/weekly work todo"
+	case "untag md":
+		command = "\n\rCommand: untag md <markdownFilePath> <tag...>"
+		description = "\n\rDescription: untag md removes the tags from the YAML front matter of the markdown file and from the metadata."
+		example = "\n\rExample Usage: untag md meetings/weekly todo"
+	case "tags":
+		command = "\n\rCommand: tags"
+		description = "\n\rDescription: tags lists every tag of the active workspace together with the number of notes that carry it. Use 'ls tag:<tag>' to list these notes."
+		example = "\n\rExample Usage: tags"
+	case "search":
+		command = "\n\rCommand: search [--all] <query>"
+		description = "\n\rDescription: search looks up the query in the search index of the markdown files of the active workspace, or of all workspaces with --all, ignoring case. Terms are combined with AND, use OR and NOT or a leading '-' to combine them differently, \"quotes\" for phrases and a trailing '*' for prefixes. The notes are ranked by relevance and every matching line is printed with a number, its node path, filename and line number. Use 'edit #<n>' to open a result."
+		example = "\n\rExample Usage: search \"quarterly review\" budget* -draft"
+	case "reindex":
+		command = "\n\rCommand: reindex"
+		description = "\n\rDescription: reindex rebuilds the search index of all workspaces from scratch. The index is kept up to date by notewolfy, which is why you only need it if notes were changed in a way that the index could not notice."
+		example = "\n\rExample Usage: reindex"
+	case "find":
+		command = "\n\rCommand: find <pattern>"
+		description = "\n\rDescription: find fuzzy matches the pattern against the names and node paths of the markdown files of the active workspace, the characters of the pattern only need to appear in order. The best matches, preferring recently modified notes, are listed with a number. Picking a number goes to the node of the note and opens it in the editor, pressing enter cancels."
+		example = "\n\rExample Usage: find mtwk"
+	case "links":
+		command = "\n\rCommand: links <markdownFilePath>"
+		description = "\n\rDescription: links lists the [[wiki links]] of the markdown file together with the notes of the active workspace that they point to. A link is either the name of a note, which is looked up on the same node first, or a node path with the name of a note, e.g. [[meetings/weekly]]. The links are updated whenever a note is created, edited or synced."
+		example = "\n\rExample Usage: links meetings/weekly"
+	case "backlinks":
+		command = "\n\rCommand: backlinks <markdownFilePath>"
+		description = "\n\rDescription: backlinks lists the notes of the active workspace that link to the markdown file with a [[wiki link]]."
+		example = "\n\rExample Usage: backlinks meetings/weekly"
+	case "check links":
+		command = "\n\rCommand: check links"
+		description = "\n\rDescription: check links walks every note of the active workspace and reports broken links with their file and line. Relative markdown links like [notes](../other/note.md) have to point to an existing file, #heading anchors to a heading of the linked note and [[wiki links]] to a note of the workspace. If a note or heading with a similar name exists, it is suggested as a fix."
+		example = "\n\rExample Usage: check links"
+	case "export graph":
+		command = "\n\rCommand: export graph --format dot|mermaid [--links] [--output <file>]"
+		description = "\n\rDescription: export graph renders the node hierarchy of the active workspace with the markdown files as leaves, either for Graphviz (dot) or as a Mermaid flowchart that can be embedded in a markdown file. With --links the wiki links and relative markdown links between notes are added as dashed edges. The graph is written to the output file or printed."
+		example = "\n\rExample Usage: export graph --format dot --links --output research.dot"
 	case "edit":
-		command = "\n\rCommand: edit <markdownFileName>"
-		description = "\n\rDescription: edit md will open the specified markdown file in vim."
-		example = "\n\rExample usage: edit example"
+		command = "\n\rCommand: edit <markdownFilePath> | edit #<n>"
+		description = "\n\rDescription: edit md will open the specified markdown file in the configured editor, which is the editor of the config file, $VISUAL, $EDITOR or vim. The name can be prefixed with a node path. With #<n> the n-th result of the last search is opened."
+		example = "\n\rExample usage: edit research/example"
 	case "goto":
-		command = "\n\rCommand: goto <nodeName>"
-		description = "\n\rDescription: goto will let you change the node, specify the name of the node that is a direct child of the node that you are on."
-		example = "\n\rExample Usage: goto example"
+		command = "\n\rCommand: goto <nodePath>"
+		description = "\n\rDescription: goto will let you change the node. The path is relative to the node that you are on, '..' refers to the parent node, a leading '/' starts at the workspace root and '-' goes to the previous node."
+		example = "\n\rExample Usage: goto research/papers/2026"
 	case "goback":
 		command = "\n\rCommand: goback"
 		description = "\n\rDescription: goback lets you go to the parent node of the node that you are currently on."
 		example = "\n\rExample Usage: goback"
 	case "open":
-		command = "\n\rCommand: open <workspaceName>"
-		description = "\n\rDescription: open lets you open another workspace, in the sense that the active node will be set to the specified workspace node."
-		example = "\n\rExample Usage: open example"
+		command = "\n\rCommand: open <workspaceName|workspacePath>"
+		description = "\n\rDescription: open lets you open another workspace, in the sense that the active node will be set to the specified workspace node. If you specify a path that starts with '~', '.' or '/', the workspace found at this path will be registered, e.g. a workspace that you copied from another machine."
+		example = "\n\rExample Usage: open example or open ~/shared/example"
+	case "undo":
+		command = "\n\rCommand: undo"
+		description = "\n\rDescription: undo reverts the last command that changed your workspaces, nodes, markdown files or the node that you are on. Deleted markdown files are restored with their content."
+		example = "\n\rExample Usage: undo"
+	case "redo":
+		command = "\n\rCommand: redo"
+		description = "\n\rDescription: redo applies the last command again that has been reverted with undo."
+		example = "\n\rExample Usage: redo"
+	case "restore":
+		command = "\n\rCommand: restore <trashItemNumber>"
+		description = "\n\rDescription: restore moves the trash item with the number listed by 'ls trash' back to its original location. Nodes that do not exist anymore on the way to the original location are recreated."
+		example = "\n\rExample Usage: restore 2"
+	case "empty-trash":
+		command = "\n\rCommand: empty-trash [<age>]"
+		description = "\n\rDescription: empty-trash permanently deletes the trash items of all workspaces that are older than the specified age, e.g. 30d or 12h. Without an age the trash_max_age of your config file is used, which defaults to 30d. Use 0 to empty the whole trash."
+		example = "\n\rExample Usage: empty-trash 7d"
+	case "sync":
+		command = "\n\rCommand: sync [--dry-run]"
+		description = "\n\rDescription: sync compares the metadata of all workspaces with the filesystem, reports added, missing and moved nodes and markdown files and applies the changes after your confirmation. With --dry-run only the report is shown."
+		example = "\n\rExample Usage: sync --dry-run"
 	case "version":
 		command = "\n\rCommand: version"
 		description = "\n\rDescription: version will print notewolfy's version."
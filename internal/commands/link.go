@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+)
+
+// resolveLinkStatement returns the node and the markdown file of a links or backlinks statement.
+func resolveLinkStatement(mmf *structure.MetadataNoteWolfyFileHandle, statement string, command string) (*structure.Node, *structure.Markdown, error) {
+	pathCaptureGroupName := "path"
+	pattern := fmt.Sprintf("^%s (?P<%s>%s)$", command, pathCaptureGroupName, nodePathPattern)
+	linkRegex := regexp.MustCompile(pattern)
+	matches := linkRegex.FindStringSubmatch(statement)
+	if len(matches) != 2 {
+		return nil, nil, fmt.Errorf("\n\rPlease use '%s <markdownFilePath>', where the path matches the regex %s!", command, nodePathPattern)
+	}
+	names := linkRegex.SubexpNames()
+	var markdownPath string
+	for i, name := range names[1:] {
+		if name == pathCaptureGroupName {
+			markdownPath = matches[i+1]
+		}
+	}
+	node, markdownName, err := resolveNamedPath(mmf, markdownPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	markdown := mmf.FindMarkdown(node, markdownName)
+	if markdown == nil {
+		return nil, nil, fmt.Errorf("\n\rMarkdown file '%s' could not be found on node '%s'!", markdownName, node.Name)
+	}
+
+	return node, markdown, nil
+}
+
+type LinksStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (ls *LinksStrategy) Run() error {
+	node, markdown, err := resolveLinkStatement(ls.mmf, ls.statement, "links")
+	if err != nil {
+		return err
+	}
+	targets, notes := ls.mmf.Links(node, markdown)
+	if len(targets) == 0 {
+		fmt.Printf("\n\rMarkdown file '%s' does not link to any note!", markdown.Filename)
+		return nil
+	}
+
+	fmt.Printf("\n\rLinks of markdown file '%s':", markdown.Filename)
+	for index, target := range targets {
+		if notes[index] == nil {
+			fmt.Printf("\n\r [[%s]] -> could not be found", target)
+			continue
+		}
+		fmt.Printf("\n\r [[%s]] -> %s", target, path.Join(notes[index].NodePath, notes[index].Markdown.Filename))
+	}
+
+	return nil
+}
+
+type BacklinksStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (bs *BacklinksStrategy) Run() error {
+	_, markdown, err := resolveLinkStatement(bs.mmf, bs.statement, "backlinks")
+	if err != nil {
+		return err
+	}
+	notes := bs.mmf.Backlinks(markdown)
+	if len(notes) == 0 {
+		fmt.Printf("\n\rNo note links to markdown file '%s'!", markdown.Filename)
+		return nil
+	}
+
+	fmt.Printf("\n\rNotes linking to markdown file '%s':", markdown.Filename)
+	for _, note := range notes {
+		fmt.Printf("\n\r %s", path.Join(note.NodePath, note.Markdown.Filename))
+	}
+
+	return nil
+}
@@ -2,25 +2,64 @@ package commands
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/RaphSku/notewolfy/internal/structure"
 )
 
 type ListStrategy struct {
-	mmf *structure.MetadataNoteWolfyFileHandle
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
 }
 
 func (ls *ListStrategy) Run() error {
-	activeNodeName := ls.mmf.ActiveNode
-	if activeNodeName == "" {
+	tagCaptureGroupName := "tag"
+	pattern := fmt.Sprintf("^ls tag:(?P<%s>%s)$", tagCaptureGroupName, tagPattern)
+	tagRegex := regexp.MustCompile(pattern)
+	if matches := tagRegex.FindStringSubmatch(ls.statement); len(matches) == 2 {
+		names := tagRegex.SubexpNames()
+		var tag string
+		for i, name := range names[1:] {
+			if name == tagCaptureGroupName {
+				tag = matches[i+1]
+			}
+		}
+		return ls.listTaggedNotes(tag)
+	}
+	// Any other argument falls back to the plain listing, like ls did before it knew about flags.
+	isDetailed := regexp.MustCompile("^ls -l$").MatchString(ls.statement)
+
+	activeNodeID := ls.mmf.ActiveNode
+	if activeNodeID == "" {
 		fmt.Print("\n\rSeems like you have not created a workspace yet! Create one with 'create workspace <workspace_name> <workspace_path>'")
 		return nil
 	}
-	activeNode := ls.mmf.FindNode(activeNodeName)
+	activeNode := ls.mmf.FindNode(activeNodeID)
 	if activeNode == nil {
 		fmt.Print("\n\rSeems like you have not created a workspace yet! At least no active node is set!")
 	}
+	if isDetailed {
+		ls.mmf.ListResourcesOnNodeInDetail(activeNode)
+		return nil
+	}
 	ls.mmf.ListResourcesOnNode(activeNode)
 
 	return nil
 }
+
+func (ls *ListStrategy) listTaggedNotes(tag string) error {
+	if ls.mmf.FindActiveWorkspace() == nil {
+		return fmt.Errorf("\n\rSeems like you have not created a workspace yet! Create one with 'create workspace <workspace_name> <workspace_path>'")
+	}
+	notes := ls.mmf.FindNotesByTag(tag)
+	if len(notes) == 0 {
+		fmt.Printf("\n\rNo note of the active workspace is tagged with '%s'!", structure.NormalizeTag(tag))
+		return nil
+	}
+	fmt.Printf("\n\rNotes tagged with '%s':", structure.NormalizeTag(tag))
+	for _, note := range notes {
+		fmt.Printf("\n\r %s\t%s", note.NodePath, note.Markdown.Filename)
+	}
+
+	return nil
+}
@@ -6,9 +6,11 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/RaphSku/notewolfy/internal/utility"
 )
 
 type CreateMarkdownStrategy struct {
@@ -17,41 +19,51 @@ type CreateMarkdownStrategy struct {
 }
 
 func (cms *CreateMarkdownStrategy) Run() error {
-	nameCaptureGroupName := "name"
-	markdownNamePattern := "[\\w]+"
-	pattern := fmt.Sprintf("create md (?P<%s>%s)", nameCaptureGroupName, markdownNamePattern)
-	markdownNameRegex := regexp.MustCompile(pattern)
-	matches := markdownNameRegex.FindStringSubmatch(cms.statement)
+	pathCaptureGroupName := "path"
+	pattern := fmt.Sprintf("create md (?P<%s>%s)", pathCaptureGroupName, nodePathPattern)
+	markdownPathRegex := regexp.MustCompile(pattern)
+	matches := markdownPathRegex.FindStringSubmatch(cms.statement)
 	if len(matches) != 2 {
-		return fmt.Errorf("\n\rPlease check whether the markdown name matches the regex %s!", markdownNamePattern)
+		return fmt.Errorf("\n\rPlease check whether the markdown path matches the regex %s!", nodePathPattern)
 	}
-	names := markdownNameRegex.SubexpNames()
-	var markdownName string
+	names := markdownPathRegex.SubexpNames()
+	var markdownPath string
 	for i, name := range names[1:] {
-		if name == nameCaptureGroupName {
-			markdownName = matches[i+1]
+		if name == pathCaptureGroupName {
+			markdownPath = matches[i+1]
 		}
 	}
+	node, markdownName, err := resolveNamedPath(cms.mmf, markdownPath)
+	if err != nil {
+		return err
+	}
 	markdownNameWithFExt := strings.Join([]string{markdownName, ".md"}, "")
-	activeNodeName := cms.mmf.ActiveNode
-	activeNode := cms.mmf.FindNode(activeNodeName)
-	pathToMarkdown := filepath.Join(activeNode.Path, markdownNameWithFExt)
+	pathToMarkdown := filepath.Join(node.Path, markdownNameWithFExt)
 
-	_, err := os.Stat(pathToMarkdown)
+	_, err = os.Stat(pathToMarkdown)
 	if os.IsNotExist(err) {
-		markdown := &structure.Markdown{
-			Filename: markdownNameWithFExt,
-		}
-		cms.mmf.AddMarkdown(markdown)
-		cms.mmf.Save()
+		markdown := structure.NewMarkdown(markdownNameWithFExt)
+		entry := cms.mmf.BeginJournalEntry(cms.statement)
+		entry.TrackMarkdown(markdown.ID)
+		entry.TrackFile(pathToMarkdown)
 
 		file, err := os.Create(pathToMarkdown)
 		if err != nil {
 			return err
 		}
-		defer file.Close()
+		file.Close()
+		if _, err := markdown.Refresh(pathToMarkdown); err != nil {
+			return err
+		}
+		cms.mmf.AddMarkdownToNode(node, markdown)
+		if err := cms.mmf.Save(); err != nil {
+			return err
+		}
+		if err := cms.mmf.CommitJournalEntry(entry); err != nil {
+			return err
+		}
 
-		return nil
+		return indexError(cms.mmf.IndexMarkdown(node, markdown))
 	}
 
 	return fmt.Errorf("\r\nMarkdown file already exists!")
@@ -63,36 +75,44 @@ type DeleteMDStrategy struct {
 }
 
 func (dms *DeleteMDStrategy) Run() error {
-	nameCaptureGroupName := "name"
-	markdownNamePattern := "[\\w]+"
-	pattern := fmt.Sprintf("delete md (?P<%s>%s)", nameCaptureGroupName, markdownNamePattern)
-	markdownNameRegex := regexp.MustCompile(pattern)
-	matches := markdownNameRegex.FindStringSubmatch(dms.statement)
+	pathCaptureGroupName := "path"
+	pattern := fmt.Sprintf("delete md (?P<%s>%s)", pathCaptureGroupName, nodePathPattern)
+	markdownPathRegex := regexp.MustCompile(pattern)
+	matches := markdownPathRegex.FindStringSubmatch(dms.statement)
 	if len(matches) != 2 {
-		return fmt.Errorf("\n\rPlease check whether the markdown name matches the regex %s!", markdownNamePattern)
+		return fmt.Errorf("\n\rPlease check whether the markdown path matches the regex %s!", nodePathPattern)
 	}
-	names := markdownNameRegex.SubexpNames()
-	var markdownName string
+	names := markdownPathRegex.SubexpNames()
+	var markdownPath string
 	for i, name := range names[1:] {
-		if name == nameCaptureGroupName {
-			markdownName = matches[i+1]
+		if name == pathCaptureGroupName {
+			markdownPath = matches[i+1]
 		}
 	}
-
-	activeNodeName := dms.mmf.ActiveNode
-	activeNode := dms.mmf.FindNode(activeNodeName)
-	err := os.Remove(filepath.Join(activeNode.Path, strings.Join([]string{markdownName, ".md"}, "")))
+	node, markdownName, err := resolveNamedPath(dms.mmf, markdownPath)
 	if err != nil {
 		return err
 	}
 
-	err = dms.mmf.DeleteMarkdown(markdownName)
+	markdown := dms.mmf.FindMarkdown(node, markdownName)
+	if markdown == nil {
+		return fmt.Errorf("\n\rMarkdown file '%s' could not be found on node '%s'!", markdownName, node.Name)
+	}
+	entry := dms.mmf.BeginJournalEntry(dms.statement)
+	err = dms.mmf.MoveMarkdownToTrash(entry, node, markdown)
+	if err != nil {
+		return err
+	}
+	err = dms.mmf.Save()
+	if err != nil {
+		return err
+	}
+	err = dms.mmf.CommitJournalEntry(entry)
 	if err != nil {
 		return err
 	}
-	dms.mmf.Save()
 
-	return nil
+	return indexError(dms.mmf.UnindexMarkdown(markdown.ID))
 }
 
 type EditStrategy struct {
@@ -101,38 +121,110 @@ type EditStrategy struct {
 }
 
 func (es *EditStrategy) Run() error {
-	nameCaptureGroupName := "name"
-	markdownNamePattern := "[\\w]+"
-	pattern := fmt.Sprintf("edit (?P<%s>%s)", nameCaptureGroupName, markdownNamePattern)
-	markdownNameRegex := regexp.MustCompile(pattern)
-	matches := markdownNameRegex.FindStringSubmatch(es.statement)
+	numberCaptureGroupName := "number"
+	resultPattern := fmt.Sprintf("^edit #(?P<%s>[0-9]+)$", numberCaptureGroupName)
+	resultRegex := regexp.MustCompile(resultPattern)
+	if matches := resultRegex.FindStringSubmatch(es.statement); len(matches) == 2 {
+		names := resultRegex.SubexpNames()
+		var number int
+		for i, name := range names[1:] {
+			if name == numberCaptureGroupName {
+				number, _ = strconv.Atoi(matches[i+1])
+			}
+		}
+		note, err := es.mmf.FindResult(number)
+		if err != nil {
+			return fmt.Errorf("\n\rCould not open search result #%d, %v!", number, err)
+		}
+		return openInEditor(es.mmf, note.Node, note.Markdown)
+	}
+
+	pathCaptureGroupName := "path"
+	pattern := fmt.Sprintf("edit (?P<%s>%s)", pathCaptureGroupName, nodePathPattern)
+	markdownPathRegex := regexp.MustCompile(pattern)
+	matches := markdownPathRegex.FindStringSubmatch(es.statement)
 	if len(matches) != 2 {
-		return fmt.Errorf("\n\rPlease check whether the markdown name matches the regex %s!", markdownNamePattern)
+		return fmt.Errorf("\n\rPlease check whether the markdown path matches the regex %s!", nodePathPattern)
 	}
-	names := markdownNameRegex.SubexpNames()
-	var markdownName string
+	names := markdownPathRegex.SubexpNames()
+	var markdownPath string
 	for i, name := range names[1:] {
-		if name == nameCaptureGroupName {
-			markdownName = matches[i+1]
+		if name == pathCaptureGroupName {
+			markdownPath = matches[i+1]
 		}
 	}
-	activeNodeName := es.mmf.ActiveNode
-	activeNode := es.mmf.FindNode(activeNodeName)
-	for _, markdown := range activeNode.Markdowns {
+	node, markdownName, err := resolveNamedPath(es.mmf, markdownPath)
+	if err != nil {
+		return err
+	}
+	for _, markdown := range node.Markdowns {
 		if markdown.Filename[:len(markdown.Filename)-3] == markdownName {
-			markdownFile := filepath.Join(activeNode.Path, markdown.Filename)
+			return openInEditor(es.mmf, node, markdown)
+		}
+	}
 
-			cmd := exec.Command("vim", markdownFile)
-			cmd.Stdin = os.Stdin
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
+	return nil
+}
 
-			err := cmd.Run()
-			if err != nil {
-				return err
-			}
+// editorCommand builds the command that opens the file, the editor mapped to the extension of the file wins over
+// the default editor and may come with arguments like 'code --wait'.
+func editorCommand(config *structure.Config, file string) (*exec.Cmd, error) {
+	editor := config.Editor
+	if extensionEditor, isMapped := config.Editors[strings.ToLower(filepath.Ext(file))]; isMapped {
+		editor = extensionEditor
+	}
+	if strings.TrimSpace(editor) == "" {
+		editor = "vim"
+	}
+	arguments, err := utility.SplitCommandLine(editor)
+	if err != nil {
+		return nil, fmt.Errorf("\n\rPlease check the configured editor, %v!", err)
+	}
+	if _, err := exec.LookPath(arguments[0]); err != nil {
+		return nil, fmt.Errorf("\n\rThe editor '%s' could not be found! Install it or configure another one with 'editor' in the config file, $VISUAL or $EDITOR.", arguments[0])
+	}
+
+	return exec.Command(arguments[0], append(arguments[1:], file)...), nil
+}
+
+// openInEditor opens the markdown file in the configured editor and refreshes its metadata afterwards.
+// The lock is released while the editor runs, so that other notewolfy sessions are not blocked by it,
+// and the metadata is read again afterwards, since another session may have changed it in the meantime.
+func openInEditor(mmf *structure.MetadataNoteWolfyFileHandle, node *structure.Node, markdown *structure.Markdown) error {
+	markdownFile := filepath.Join(node.Path, markdown.Filename)
+
+	cmd, err := editorCommand(mmf.Config, markdownFile)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := mmf.Unlock(); err != nil {
+		return err
+	}
+	editorErr := cmd.Run()
+	if err := mmf.Lock(); err != nil {
+		return err
+	}
+	if editorErr != nil {
+		return fmt.Errorf("\n\rThe editor '%s' exited with an error, %v!", cmd.Args[0], editorErr)
+	}
+	node, markdown = mmf.FindMarkdownByID(markdown.ID)
+	if markdown == nil {
+		return fmt.Errorf("\n\rThe markdown file has been removed by another notewolfy session while it was edited!")
+	}
+
+	isRefreshed, err := mmf.RefreshMarkdown(node, markdown)
+	if err != nil {
+		return err
+	}
+	if isRefreshed {
+		if err := mmf.Save(); err != nil {
+			return err
 		}
 	}
 
-	return nil
+	return indexError(mmf.IndexMarkdown(node, markdown))
 }
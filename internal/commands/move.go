@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+)
+
+const targetNodePathPattern = "(?:[\\w]+:)?[\\w./-]*"
+
+// parseMoveStatement returns the source path and the target node path of a mv statement.
+func parseMoveStatement(statement string, resource string) (string, string, error) {
+	sourceCaptureGroupName := "source"
+	targetCaptureGroupName := "target"
+	pattern := fmt.Sprintf("^mv %s (?P<%s>%s) (?P<%s>%s)$", resource, sourceCaptureGroupName, nodePathPattern, targetCaptureGroupName, targetNodePathPattern)
+	moveRegex := regexp.MustCompile(pattern)
+	matches := moveRegex.FindStringSubmatch(statement)
+	if len(matches) != 3 {
+		return "", "", fmt.Errorf("\n\rPlease use 'mv %s <path> <targetNodePath>', where the path matches the regex %s and the target node path matches the regex %s!", resource, nodePathPattern, targetNodePathPattern)
+	}
+	names := moveRegex.SubexpNames()
+	var sourcePath string
+	var targetPath string
+	for i, name := range names[1:] {
+		if name == sourceCaptureGroupName {
+			sourcePath = matches[i+1]
+		} else if name == targetCaptureGroupName {
+			targetPath = matches[i+1]
+		}
+	}
+
+	return sourcePath, targetPath, nil
+}
+
+func resolveTargetNode(mmf *structure.MetadataNoteWolfyFileHandle, targetPath string) (*structure.Node, error) {
+	_, node, err := mmf.ResolveWorkspaceNodePath(targetPath)
+	if err != nil {
+		return nil, fmt.Errorf("\n\rPlease check the path '%s', %v!", targetPath, err)
+	}
+
+	return node, nil
+}
+
+type MoveMarkdownStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (mms *MoveMarkdownStrategy) Run() error {
+	markdownPath, targetPath, err := parseMoveStatement(mms.statement, "md")
+	if err != nil {
+		return err
+	}
+	node, markdownName, err := resolveNamedPath(mms.mmf, markdownPath)
+	if err != nil {
+		return err
+	}
+	markdown := mms.mmf.FindMarkdown(node, markdownName)
+	if markdown == nil {
+		return fmt.Errorf("\n\rMarkdown file '%s' could not be found on node '%s'!", markdownName, node.Name)
+	}
+	targetNode, err := resolveTargetNode(mms.mmf, targetPath)
+	if err != nil {
+		return err
+	}
+
+	entry := mms.mmf.BeginJournalEntry(mms.statement)
+	if err := mms.mmf.MoveMarkdown(entry, node, markdown, targetNode); err != nil {
+		return fmt.Errorf("\n\rCould not move '%s', %v!", markdownName, err)
+	}
+	if err := mms.mmf.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rMoved markdown file '%s' to '%s'!", markdownName, targetPath)
+
+	return mms.mmf.CommitJournalEntry(entry)
+}
+
+type MoveNodeStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (mns *MoveNodeStrategy) Run() error {
+	nodePath, targetPath, err := parseMoveStatement(mns.statement, "node")
+	if err != nil {
+		return err
+	}
+	node, err := resolveNode(mns.mmf, nodePath)
+	if err != nil {
+		return err
+	}
+	targetNode, err := resolveTargetNode(mns.mmf, targetPath)
+	if err != nil {
+		return err
+	}
+
+	entry := mns.mmf.BeginJournalEntry(mns.statement)
+	if err := mns.mmf.MoveNode(entry, node, targetNode); err != nil {
+		return fmt.Errorf("\n\rCould not move '%s', %v!", node.Name, err)
+	}
+	if err := mns.mmf.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rMoved node '%s' to '%s'!", node.Name, targetPath)
+
+	return mns.mmf.CommitJournalEntry(entry)
+}
@@ -17,12 +17,11 @@ type CreateNodeStrategy struct {
 
 func (cns *CreateNodeStrategy) Run() error {
 	nameCaptureGroupName := "name"
-	nodeNamePattern := "[\\w]+"
-	pattern := fmt.Sprintf("create node (?P<%s>%s)", nameCaptureGroupName, nodeNamePattern)
+	pattern := fmt.Sprintf("create node (?P<%s>%s)", nameCaptureGroupName, namePattern)
 	nodeNameRegex := regexp.MustCompile(pattern)
 	matches := nodeNameRegex.FindStringSubmatch(cns.statement)
 	if len(matches) != 2 {
-		return fmt.Errorf("\n\rPlease check whether the node name matches the regex %s!", nodeNamePattern)
+		return fmt.Errorf("\n\rPlease check whether the node name matches the regex %s!", namePattern)
 	}
 	names := nodeNameRegex.SubexpNames()
 	var nodeName string
@@ -32,33 +31,31 @@ func (cns *CreateNodeStrategy) Run() error {
 		}
 	}
 
-	activeNodeName := cns.mmf.ActiveNode
-	activeNode := cns.mmf.FindNode(activeNodeName)
+	activeNodeID := cns.mmf.ActiveNode
+	activeNode := cns.mmf.FindNode(activeNodeID)
 	pathToNode, err := utility.ExpandRelativePaths(filepath.Join(activeNode.Path, nodeName))
 	if err != nil {
 		return err
 	}
 
-	var children []*structure.Node
-	var markdowns []*structure.Markdown
-	childNode := &structure.Node{
-		Name:      nodeName,
-		Path:      pathToNode,
-		Markdowns: markdowns,
-		Children:  children,
-	}
+	childNode := structure.NewNode(nodeName, pathToNode)
+	entry := cns.mmf.BeginJournalEntry(cns.statement)
+	entry.TrackNode(childNode.ID)
+	entry.TrackFile(pathToNode)
 	err = cns.mmf.AddChild(childNode)
 	if err != nil {
 		return err
 	}
-	cns.mmf.Save()
-
 	err = os.Mkdir(pathToNode, 0750)
 	if err != nil {
+		cns.mmf.DeleteChild(activeNode, childNode.ID)
+		return err
+	}
+	if err := cns.mmf.Save(); err != nil {
 		return err
 	}
 
-	return nil
+	return cns.mmf.CommitJournalEntry(entry)
 }
 
 type DeleteNodeStrategy struct {
@@ -67,47 +64,59 @@ type DeleteNodeStrategy struct {
 }
 
 func (dns *DeleteNodeStrategy) Run() error {
-	nameCaptureGroupName := "name"
-	nodeNamePattern := "[\\w]+"
-	pattern := fmt.Sprintf("delete node (?P<%s>%s)", nameCaptureGroupName, nodeNamePattern)
-	nodeNameRegex := regexp.MustCompile(pattern)
-	matches := nodeNameRegex.FindStringSubmatch(dns.statement)
-	if len(matches) != 2 {
-		return fmt.Errorf("\n\rPlease check whether the node name matches the regex %s!", nodeNamePattern)
+	flagCaptureGroupName := "flag"
+	pathCaptureGroupName := "path"
+	pattern := fmt.Sprintf("delete node (?:(?P<%s>-r) )?(?P<%s>%s)", flagCaptureGroupName, pathCaptureGroupName, nodePathPattern)
+	nodePathRegex := regexp.MustCompile(pattern)
+	matches := nodePathRegex.FindStringSubmatch(dns.statement)
+	if len(matches) != 3 {
+		return fmt.Errorf("\n\rPlease check whether the node path matches the regex %s!", nodePathPattern)
 	}
-	names := nodeNameRegex.SubexpNames()
-	var nodeName string
+	names := nodePathRegex.SubexpNames()
+	var nodePath string
+	recursive := false
 	for i, name := range names[1:] {
-		if name == nameCaptureGroupName {
-			nodeName = matches[i+1]
+		if name == pathCaptureGroupName {
+			nodePath = matches[i+1]
+		} else if name == flagCaptureGroupName {
+			recursive = matches[i+1] != ""
 		}
 	}
 
-	activeNodeName := dns.mmf.ActiveNode
-	activeNode := dns.mmf.FindNode(activeNodeName)
-	for index, child := range activeNode.Children {
-		if child.Name == nodeName {
-			if len(child.Markdowns) != 0 || len(child.Children) != 0 {
-				return fmt.Errorf("Please delete all subsequent nodes and markdown files before deleting '%s'!", nodeName)
-			}
-
-			err := dns.mmf.DeleteChildByIndex(index)
-			if err != nil {
-				return err
-			}
-			err = dns.mmf.Save()
-			if err != nil {
-				return err
-			}
-
-			err = os.Remove(child.Path)
-			if err != nil {
-				return err
-			}
-			fmt.Printf("\n\rDeleted node '%s' successfully!", nodeName)
-			return nil
+	node, err := resolveNode(dns.mmf, nodePath)
+	if err != nil {
+		return err
+	}
+	parentNode := dns.mmf.FindParentNode(node.ID)
+	if parentNode == nil {
+		return fmt.Errorf("\n\rThe workspace root cannot be deleted with 'delete node', use 'delete workspace' instead!")
+	}
+	if node.ID == dns.mmf.ActiveNode || node.Contains(dns.mmf.ActiveNode) {
+		return fmt.Errorf("\n\rYou cannot delete the node '%s' that you are currently on!", node.Name)
+	}
+	if !recursive && (len(node.Markdowns) != 0 || len(node.Children) != 0) {
+		return fmt.Errorf("Please delete all subsequent nodes and markdown files before deleting '%s' or use 'delete node -r %s'!", node.Name, nodePath)
+	}
+	if recursive {
+		confirmed, err := confirmDeletion(fmt.Sprintf("node '%s'", node.Name), node)
+		if err != nil || !confirmed {
+			return err
 		}
 	}
 
-	return fmt.Errorf("There is no node with the name '%s'!", nodeName)
+	entry := dns.mmf.BeginJournalEntry(dns.statement)
+	entry.TrackPosition()
+	err = dns.mmf.MoveNodeToTrash(entry, node)
+	if err != nil {
+		return err
+	}
+	if dns.mmf.PreviousNode == node.ID || node.Contains(dns.mmf.PreviousNode) {
+		dns.mmf.PreviousNode = ""
+	}
+	err = dns.mmf.Save()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n\rDeleted node '%s' successfully!", node.Name)
+	return dns.mmf.CommitJournalEntry(entry)
 }
@@ -13,6 +13,14 @@ type OpenStrategy struct {
 }
 
 func (ops *OpenStrategy) Run() error {
+	pathCaptureGroupName := "path"
+	workspacePathPattern := "[~./].*"
+	pathPattern := fmt.Sprintf("^open (?P<%s>%s)$", pathCaptureGroupName, workspacePathPattern)
+	workspacePathRegex := regexp.MustCompile(pathPattern)
+	if pathMatches := workspacePathRegex.FindStringSubmatch(ops.statement); len(pathMatches) == 2 {
+		return ops.openWorkspaceOnDisk(pathMatches[1])
+	}
+
 	nameCaptureGroupName := "name"
 	workspaceNamePattern := "[\\w]+"
 	pattern := fmt.Sprintf("open (?P<%s>%s)", nameCaptureGroupName, workspaceNamePattern)
@@ -29,13 +37,14 @@ func (ops *OpenStrategy) Run() error {
 		}
 	}
 
+	entry := ops.mmf.BeginJournalEntry(ops.statement)
+	entry.TrackPosition()
 	foundWorkspace := false
 	for _, workspace := range ops.mmf.Workspaces {
 		if workspace.Name == workspaceName {
 			ops.mmf.ActiveWorkspace = workspace.Name
-			ops.mmf.ActiveNode = workspace.Name
-			ops.mmf.Save()
-
+			ops.mmf.ActiveNode = workspace.ID
+			ops.mmf.PreviousNode = ""
 			foundWorkspace = true
 		}
 	}
@@ -43,6 +52,32 @@ func (ops *OpenStrategy) Run() error {
 	if !foundWorkspace {
 		return fmt.Errorf("\n\rDid not find workspace '%s'! Please specify an existing workspace.", workspaceName)
 	}
+	if err := ops.mmf.Save(); err != nil {
+		return err
+	}
+
+	return ops.mmf.CommitJournalEntry(entry)
+}
+
+func (ops *OpenStrategy) openWorkspaceOnDisk(workspacePath string) error {
+	workspace, err := structure.FindWorkspaceOnDisk(workspacePath)
+	if err != nil {
+		return fmt.Errorf("\n\rCould not open the workspace at %s, %v!", workspacePath, err)
+	}
+	entry := ops.mmf.BeginJournalEntry(ops.statement)
+	entry.TrackNode(workspace.ID)
+	entry.TrackPosition()
+	workspace, err = ops.mmf.OpenWorkspace(workspace)
+	if err != nil {
+		return fmt.Errorf("\n\rCould not open the workspace at %s, %v!", workspacePath, err)
+	}
+	if err := ops.mmf.Save(); err != nil {
+		return err
+	}
+	if err := ops.mmf.CommitJournalEntry(entry); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rOpened workspace '%s' at %s!", workspace.Name, workspace.Path)
 
 	return nil
 }
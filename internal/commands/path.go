@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+)
+
+const (
+	nodePathPattern = "[\\w./-]+"
+	namePattern     = structure.NamePattern
+)
+
+var (
+	nameRegex     = regexp.MustCompile(fmt.Sprintf("^%s$", namePattern))
+	nodePathRegex = regexp.MustCompile(fmt.Sprintf("^%s$", nodePathPattern))
+)
+
+func resolveNode(mmf *structure.MetadataNoteWolfyFileHandle, nodePath string) (*structure.Node, error) {
+	node, err := mmf.ResolveNodePath(nodePath)
+	if err != nil {
+		return nil, fmt.Errorf("\n\rPlease check the path '%s', %v!", nodePath, err)
+	}
+
+	return node, nil
+}
+
+func resolveNamedPath(mmf *structure.MetadataNoteWolfyFileHandle, namedPath string) (*structure.Node, string, error) {
+	nodePath, name := structure.SplitNodePath(namedPath)
+	if !nameRegex.MatchString(name) {
+		return nil, "", fmt.Errorf("\n\rPlease check whether the name '%s' matches the regex %s!", name, namePattern)
+	}
+	node, err := resolveNode(mmf, nodePath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return node, name, nil
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+)
+
+// parseRenameStatement returns the old path or name and the new name of a rename statement.
+func parseRenameStatement(statement string, resource string, oldPattern string) (string, string, error) {
+	oldCaptureGroupName := "old"
+	newCaptureGroupName := "new"
+	pattern := fmt.Sprintf("^rename %s (?P<%s>%s) (?P<%s>%s)$", resource, oldCaptureGroupName, oldPattern, newCaptureGroupName, namePattern)
+	renameRegex := regexp.MustCompile(pattern)
+	matches := renameRegex.FindStringSubmatch(statement)
+	if len(matches) != 3 {
+		return "", "", fmt.Errorf("\n\rPlease use 'rename %s <old> <new>', where the old name matches the regex %s and the new name matches the regex %s!", resource, oldPattern, namePattern)
+	}
+	names := renameRegex.SubexpNames()
+	var oldName string
+	var newName string
+	for i, name := range names[1:] {
+		if name == oldCaptureGroupName {
+			oldName = matches[i+1]
+		} else if name == newCaptureGroupName {
+			newName = matches[i+1]
+		}
+	}
+
+	return oldName, newName, nil
+}
+
+type RenameMarkdownStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (rms *RenameMarkdownStrategy) Run() error {
+	markdownPath, newName, err := parseRenameStatement(rms.statement, "md", nodePathPattern)
+	if err != nil {
+		return err
+	}
+	node, markdownName, err := resolveNamedPath(rms.mmf, markdownPath)
+	if err != nil {
+		return err
+	}
+	markdown := rms.mmf.FindMarkdown(node, markdownName)
+	if markdown == nil {
+		return fmt.Errorf("\n\rMarkdown file '%s' could not be found on node '%s'!", markdownName, node.Name)
+	}
+
+	entry := rms.mmf.BeginJournalEntry(rms.statement)
+	if err := rms.mmf.RenameMarkdown(entry, node, markdown, newName); err != nil {
+		return fmt.Errorf("\n\rCould not rename '%s', %v!", markdownName, err)
+	}
+	if err := rms.mmf.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rRenamed markdown file '%s' to '%s'!", markdownName, newName)
+	if err := rms.mmf.CommitJournalEntry(entry); err != nil {
+		return err
+	}
+
+	return indexError(rms.mmf.IndexMarkdown(node, markdown))
+}
+
+type RenameNodeStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (rns *RenameNodeStrategy) Run() error {
+	nodePath, newName, err := parseRenameStatement(rns.statement, "node", nodePathPattern)
+	if err != nil {
+		return err
+	}
+	node, err := resolveNode(rns.mmf, nodePath)
+	if err != nil {
+		return err
+	}
+	oldName := node.Name
+
+	entry := rns.mmf.BeginJournalEntry(rns.statement)
+	if err := rns.mmf.RenameNode(entry, node, newName); err != nil {
+		return fmt.Errorf("\n\rCould not rename '%s', %v!", oldName, err)
+	}
+	if err := rns.mmf.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rRenamed node '%s' to '%s'!", oldName, newName)
+	if err := rns.mmf.CommitJournalEntry(entry); err != nil {
+		return err
+	}
+
+	return indexError(rns.mmf.IndexNode(node))
+}
+
+type RenameWorkspaceStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (rws *RenameWorkspaceStrategy) Run() error {
+	oldName, newName, err := parseRenameStatement(rws.statement, "workspace", namePattern)
+	if err != nil {
+		return err
+	}
+	var workspace *structure.Node
+	for _, registeredWorkspace := range rws.mmf.Workspaces {
+		if registeredWorkspace.Name == oldName {
+			workspace = registeredWorkspace
+		}
+	}
+	if workspace == nil {
+		return fmt.Errorf("\n\rWorkspace '%s' could not be found!", oldName)
+	}
+
+	entry := rws.mmf.BeginJournalEntry(rws.statement)
+	if err := rws.mmf.RenameWorkspace(entry, workspace, newName); err != nil {
+		return fmt.Errorf("\n\rCould not rename '%s', %v!", oldName, err)
+	}
+	if err := rws.mmf.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rRenamed workspace '%s' to '%s'!", oldName, newName)
+
+	return rws.mmf.CommitJournalEntry(entry)
+}
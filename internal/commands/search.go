@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+)
+
+const (
+	highlightStart = "\033[1;31m"
+	highlightEnd   = "\033[0m"
+)
+
+// indexError tells the user how to recover from an index that could not be updated, the command itself succeeded.
+func indexError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("\n\rCould not update the search index, %v! Use 'reindex' to rebuild it.", err)
+}
+
+type SearchStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (ss *SearchStrategy) Run() error {
+	flagCaptureGroupName := "flag"
+	queryCaptureGroupName := "query"
+	pattern := fmt.Sprintf("^search(?: (?P<%s>--all))? (?P<%s>.+)$", flagCaptureGroupName, queryCaptureGroupName)
+	searchRegex := regexp.MustCompile(pattern)
+	matches := searchRegex.FindStringSubmatch(ss.statement)
+	if len(matches) != 3 {
+		return fmt.Errorf("\n\rPlease use 'search [--all] <query>'!")
+	}
+	names := searchRegex.SubexpNames()
+	var allWorkspaces bool
+	var query string
+	for i, name := range names[1:] {
+		if name == flagCaptureGroupName {
+			allWorkspaces = matches[i+1] != ""
+		} else if name == queryCaptureGroupName {
+			query = matches[i+1]
+		}
+	}
+
+	_, err := SearchNotes(ss.mmf, query, allWorkspaces)
+	return err
+}
+
+// SearchNotes prints the numbered hits of the query and remembers them, so that 'edit #<n>' can open them.
+// It returns the number of hits.
+func SearchNotes(mmf *structure.MetadataNoteWolfyFileHandle, query string, allWorkspaces bool) (int, error) {
+	hits, err := mmf.Search(query, allWorkspaces)
+	if err != nil {
+		return 0, fmt.Errorf("\n\rCould not search for '%s', %v!", query, err)
+	}
+	notes := make([]*structure.NoteReference, 0, len(hits))
+	for _, hit := range hits {
+		notes = append(notes, hit.Note)
+	}
+	if err := mmf.SaveResults(notes); err != nil {
+		return 0, err
+	}
+	if len(hits) == 0 {
+		fmt.Printf("\n\rNo note contains '%s'!", query)
+		return 0, nil
+	}
+
+	for number, hit := range hits {
+		location := hit.Note.NodePath
+		if allWorkspaces {
+			location = hit.Note.Workspace + structure.WorkspacePathSeparator + location
+		}
+		snippet := hit.Snippet[:hit.MatchStart] + highlightStart + hit.Snippet[hit.MatchStart:hit.MatchEnd] + highlightEnd + hit.Snippet[hit.MatchEnd:]
+		fmt.Printf("\n\r[%d] %s %s:%d: %s", number+1, location, hit.Note.Markdown.Filename, hit.Line, snippet)
+	}
+	fmt.Print("\n\rUse 'edit #<n>' to open a result.")
+
+	return len(hits), nil
+}
+
+type ReindexStrategy struct {
+	mmf *structure.MetadataNoteWolfyFileHandle
+}
+
+func (rs *ReindexStrategy) Run() error {
+	count, err := rs.mmf.Reindex()
+	if err != nil {
+		return fmt.Errorf("\n\rCould not rebuild the search index, %v!", err)
+	}
+	fmt.Printf("\n\rIndexed %d notes!", count)
+
+	return nil
+}
@@ -17,10 +17,25 @@ func matchStatementToStrategy(mmf *structure.MetadataNoteWolfyFileHandle, statem
 			statement: statement,
 			mmf:       mmf,
 		},
+		"adopt workspace": &AdoptWorkspaceStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
 		"ls ws": &ListWorkspacesStrategy{
 			statement: statement,
 			mmf:       mmf,
 		},
+		"ls trash": &ListTrashStrategy{
+			mmf: mmf,
+		},
+		"restore": &RestoreStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"empty-trash": &EmptyTrashStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
 		"create node": &CreateNodeStrategy{
 			statement: statement,
 			mmf:       mmf,
@@ -37,6 +52,34 @@ func matchStatementToStrategy(mmf *structure.MetadataNoteWolfyFileHandle, statem
 			statement: statement,
 			mmf:       mmf,
 		},
+		"rename md": &RenameMarkdownStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"rename node": &RenameNodeStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"rename workspace": &RenameWorkspaceStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"mv md": &MoveMarkdownStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"mv node": &MoveNodeStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"cp md": &CopyMarkdownStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"cp node": &CopyNodeStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
 		"open": &OpenStrategy{
 			statement: statement,
 			mmf:       mmf,
@@ -46,8 +89,51 @@ func matchStatementToStrategy(mmf *structure.MetadataNoteWolfyFileHandle, statem
 			mmf:       mmf,
 		},
 		"ls": &ListStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"tag md": &TagMarkdownStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"untag md": &UntagMarkdownStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"tags": &TagsStrategy{
+			mmf: mmf,
+		},
+		"search": &SearchStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"reindex": &ReindexStrategy{
 			mmf: mmf,
 		},
+		"find": &FindStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"links": &LinksStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"backlinks": &BacklinksStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"check links": &CheckLinksStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"export graph": &ExportGraphStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
+		"tree": &TreeStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
 		"goto": &GoToStrategy{
 			statement: statement,
 			mmf:       mmf,
@@ -55,6 +141,16 @@ func matchStatementToStrategy(mmf *structure.MetadataNoteWolfyFileHandle, statem
 		"goback": &GoBackStrategy{
 			mmf: mmf,
 		},
+		"undo": &UndoStrategy{
+			mmf: mmf,
+		},
+		"redo": &RedoStrategy{
+			mmf: mmf,
+		},
+		"sync": &SyncStrategy{
+			statement: statement,
+			mmf:       mmf,
+		},
 		"help": &HelpStrategy{
 			statement: statement,
 		},
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/RaphSku/notewolfy/internal/utility"
+)
+
+type SyncStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (ss *SyncStrategy) Run() error {
+	flagCaptureGroupName := "flag"
+	pattern := fmt.Sprintf("^sync(?: (?P<%s>--dry-run))?$", flagCaptureGroupName)
+	syncRegex := regexp.MustCompile(pattern)
+	matches := syncRegex.FindStringSubmatch(ss.statement)
+	if len(matches) != 2 {
+		return fmt.Errorf("\n\rPlease use either 'sync' or 'sync --dry-run'!")
+	}
+	dryRun := matches[1] != ""
+
+	_, err := SyncWorkspaces(ss.mmf, dryRun, false, os.Stdin)
+	return err
+}
+
+// SyncWorkspaces reconciles the metadata of all workspaces with the filesystem and reports
+// whether the metadata differed from the filesystem.
+func SyncWorkspaces(mmf *structure.MetadataNoteWolfyFileHandle, dryRun bool, assumeYes bool, input io.Reader) (bool, error) {
+	var reports []*structure.SyncReport
+	hasChanges := false
+	for _, workspace := range mmf.Workspaces {
+		report, err := mmf.DiffWorkspace(workspace)
+		if err != nil {
+			fmt.Printf("\n\rSkipping workspace '%s' since it could not be scanned: %v", workspace.Name, err)
+			continue
+		}
+		report.Print()
+		if report.HasChanges() {
+			hasChanges = true
+		}
+		if report.HasChanges() || report.HasRefreshedNotes() {
+			reports = append(reports, report)
+		}
+	}
+
+	if len(reports) == 0 || dryRun {
+		return hasChanges, nil
+	}
+
+	if hasChanges && !assumeYes {
+		confirmed, err := utility.AskForConfirmation(input, "Do you want to apply these changes to the metadata?")
+		if err != nil {
+			return hasChanges, err
+		}
+		if !confirmed {
+			fmt.Print("\n\rSync aborted, the metadata was not changed!")
+			return hasChanges, nil
+		}
+	}
+
+	entry := mmf.BeginJournalEntry("sync")
+	entry.TrackPosition()
+	for _, report := range reports {
+		entry.TrackNode(report.Workspace.ID)
+		mmf.ApplySyncReport(report)
+	}
+	if err := mmf.Save(); err != nil {
+		return hasChanges, err
+	}
+	if err := mmf.CommitJournalEntry(entry); err != nil {
+		return hasChanges, err
+	}
+	if hasChanges {
+		fmt.Print("\n\rSynced the metadata with the filesystem successfully!")
+	}
+
+	return hasChanges, nil
+}
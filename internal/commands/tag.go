@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+)
+
+const tagPattern = "#?[\\w/-]+"
+
+// parseTagStatement returns the markdown path and the tags of a tag or untag statement.
+func parseTagStatement(statement string, command string) (string, []string, error) {
+	pathCaptureGroupName := "path"
+	tagsCaptureGroupName := "tags"
+	pattern := fmt.Sprintf("^%s md (?P<%s>%s)(?P<%s>(?: %s)+)$", command, pathCaptureGroupName, nodePathPattern, tagsCaptureGroupName, tagPattern)
+	tagRegex := regexp.MustCompile(pattern)
+	matches := tagRegex.FindStringSubmatch(statement)
+	if len(matches) != 3 {
+		return "", nil, fmt.Errorf("\n\rPlease use '%s md <markdownFilePath> <tag...>', where every tag matches the regex %s!", command, tagPattern)
+	}
+	names := tagRegex.SubexpNames()
+	var markdownPath string
+	var tags []string
+	for i, name := range names[1:] {
+		if name == pathCaptureGroupName {
+			markdownPath = matches[i+1]
+		} else if name == tagsCaptureGroupName {
+			tags = strings.Fields(matches[i+1])
+		}
+	}
+
+	return markdownPath, tags, nil
+}
+
+type TagMarkdownStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (tms *TagMarkdownStrategy) Run() error {
+	return runTagStatement(tms.mmf, tms.statement, "tag")
+}
+
+type UntagMarkdownStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (ums *UntagMarkdownStrategy) Run() error {
+	return runTagStatement(ums.mmf, ums.statement, "untag")
+}
+
+func runTagStatement(mmf *structure.MetadataNoteWolfyFileHandle, statement string, command string) error {
+	markdownPath, tags, err := parseTagStatement(statement, command)
+	if err != nil {
+		return err
+	}
+	node, markdownName, err := resolveNamedPath(mmf, markdownPath)
+	if err != nil {
+		return err
+	}
+	markdown := mmf.FindMarkdown(node, markdownName)
+	if markdown == nil {
+		return fmt.Errorf("\n\rMarkdown file '%s' could not be found on node '%s'!", markdownName, node.Name)
+	}
+
+	entry := mmf.BeginJournalEntry(statement)
+	if command == "tag" {
+		err = mmf.TagMarkdown(entry, node, markdown, tags)
+	} else {
+		err = mmf.UntagMarkdown(entry, node, markdown, tags)
+	}
+	if err != nil {
+		return fmt.Errorf("\n\rCould not update the tags of '%s', %v!", markdownName, err)
+	}
+	if err := mmf.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rTags of markdown file '%s': [%s]", markdownName, strings.Join(markdown.Tags, ", "))
+
+	return mmf.CommitJournalEntry(entry)
+}
+
+type TagsStrategy struct {
+	mmf *structure.MetadataNoteWolfyFileHandle
+}
+
+func (ts *TagsStrategy) Run() error {
+	if ts.mmf.FindActiveWorkspace() == nil {
+		return fmt.Errorf("\n\rSeems like you have not created a workspace yet! Create one with 'create workspace <workspace_name> <workspace_path>'")
+	}
+	tagCounts := ts.mmf.CountTags()
+	if len(tagCounts) == 0 {
+		fmt.Print("\n\rNo note of the active workspace is tagged yet!")
+		return nil
+	}
+	tags := make([]string, 0, len(tagCounts))
+	for tag := range tagCounts {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	for _, tag := range tags {
+		fmt.Printf("\n\r%s (%d)", tag, tagCounts[tag])
+	}
+
+	return nil
+}
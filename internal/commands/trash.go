@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/RaphSku/notewolfy/internal/utility"
+)
+
+const trashTimeFormat = "2006-01-02 15:04"
+
+// confirmDeletion summarizes what is removed together with the node and asks the user for confirmation.
+func confirmDeletion(description string, node *structure.Node) (bool, error) {
+	nodeCount, markdownCount := node.CountDescendants()
+	size, err := utility.DiskUsage(node.Path)
+	if err != nil {
+		return false, err
+	}
+	fmt.Printf("\n\rDeleting %s removes it together with %d nodes and %d markdown files below it, %s on disk:", description, nodeCount, markdownCount, utility.FormatBytes(size))
+	printSubtree(node, "")
+
+	confirmed, err := utility.AskForConfirmation(os.Stdin, "Do you want to move all of it to the trash?")
+	if err != nil {
+		return false, err
+	}
+	if !confirmed {
+		fmt.Print("\n\rDeletion aborted, nothing was deleted!")
+	}
+
+	return confirmed, nil
+}
+
+func printSubtree(node *structure.Node, indentation string) {
+	for _, markdown := range node.Markdowns {
+		fmt.Printf("\n\r%s- %s", indentation, markdown.Filename)
+	}
+	for _, child := range node.Children {
+		fmt.Printf("\n\r%s+ %s", indentation, child.Name)
+		printSubtree(child, indentation+"  ")
+	}
+}
+
+type ListTrashStrategy struct {
+	mmf *structure.MetadataNoteWolfyFileHandle
+}
+
+func (lts *ListTrashStrategy) Run() error {
+	items := lts.mmf.ListTrash()
+	if len(items) == 0 {
+		fmt.Print("\n\rThe trash is empty!")
+		return nil
+	}
+
+	headers := []string{"#", "Kind", "Name", "Original Location", "Deleted At"}
+	var rows [][]string
+	width := len(trashTimeFormat)
+	for index, item := range items {
+		originalLocation := item.OriginalPath
+		if item.Kind != structure.TrashWorkspace {
+			originalLocation = path.Join(item.OriginalPath, item.Name)
+		}
+		row := []string{strconv.Itoa(index + 1), string(item.Kind), item.Name, originalLocation, item.DeletedAt.Format(trashTimeFormat)}
+		for _, entry := range row {
+			width = max(width, len(entry))
+		}
+		rows = append(rows, row)
+	}
+
+	table := utility.NewTable(width + 2)
+	table.SetHeaders(headers)
+	for _, row := range rows {
+		if err := table.Append(row); err != nil {
+			return err
+		}
+	}
+	fmt.Print("\n\r")
+
+	return table.GenerateTable()
+}
+
+type RestoreStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (rs *RestoreStrategy) Run() error {
+	indexCaptureGroupName := "index"
+	indexPattern := "[0-9]+"
+	pattern := fmt.Sprintf("^restore (?P<%s>%s)$", indexCaptureGroupName, indexPattern)
+	restoreRegex := regexp.MustCompile(pattern)
+	matches := restoreRegex.FindStringSubmatch(rs.statement)
+	if len(matches) != 2 {
+		return fmt.Errorf("\n\rPlease specify the number of the trash item as listed by 'ls trash'!")
+	}
+	names := restoreRegex.SubexpNames()
+	var itemNumber int
+	for i, name := range names[1:] {
+		if name == indexCaptureGroupName {
+			itemNumber, _ = strconv.Atoi(matches[i+1])
+		}
+	}
+
+	items := rs.mmf.ListTrash()
+	if itemNumber < 1 || itemNumber > len(items) {
+		return fmt.Errorf("\n\rThere is no trash item with the number %d, use 'ls trash' to list the trash!", itemNumber)
+	}
+	item := items[itemNumber-1]
+
+	entry := rs.mmf.BeginJournalEntry(rs.statement)
+	if err := rs.mmf.RestoreTrashItem(entry, item); err != nil {
+		return fmt.Errorf("\n\rCould not restore '%s', %v!", item.Name, err)
+	}
+	if err := rs.mmf.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rRestored %s '%s'!", item.Kind, item.Name)
+
+	return rs.mmf.CommitJournalEntry(entry)
+}
+
+type EmptyTrashStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (ets *EmptyTrashStrategy) Run() error {
+	ageCaptureGroupName := "age"
+	agePattern := "[0-9]+[a-z]*"
+	pattern := fmt.Sprintf("^empty-trash(?: (?P<%s>%s))?$", ageCaptureGroupName, agePattern)
+	emptyTrashRegex := regexp.MustCompile(pattern)
+	matches := emptyTrashRegex.FindStringSubmatch(ets.statement)
+	if len(matches) != 2 {
+		return fmt.Errorf("\n\rPlease check whether the age matches the regex %s, e.g. 30d or 12h!", agePattern)
+	}
+	names := emptyTrashRegex.SubexpNames()
+	var age string
+	for i, name := range names[1:] {
+		if name == ageCaptureGroupName {
+			age = matches[i+1]
+		}
+	}
+
+	maxAge := ets.mmf.Config.TrashMaxAge
+	if age != "" {
+		var err error
+		maxAge, err = utility.ParseAge(age)
+		if err != nil {
+			return fmt.Errorf("\n\r%v!", err)
+		}
+	}
+	removedCount, err := ets.mmf.EmptyTrash(maxAge, time.Now())
+	if saveErr := ets.mmf.Save(); err == nil {
+		err = saveErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n\rRemoved %d items from the trash!", removedCount)
+
+	return nil
+}
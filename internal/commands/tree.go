@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+)
+
+type TreeStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+// parseTreeStatement returns the node path, the depth limit and whether notes are drawn, the flags may be given in any order.
+func parseTreeStatement(statement string) (string, int, bool, error) {
+	usageError := fmt.Errorf("\n\rPlease use 'tree [<nodePath>] [-L <depth>] [--notes]'!")
+	arguments := strings.Split(statement, " ")
+	if arguments[0] != "tree" {
+		return "", 0, false, usageError
+	}
+	nodePath := structure.NodePathSeparator
+	isPathSet := false
+	maxDepth := 0
+	withNotes := false
+	for index := 1; index < len(arguments); index++ {
+		switch argument := arguments[index]; {
+		case argument == "--notes":
+			withNotes = true
+		case argument == "-L":
+			if index+1 == len(arguments) {
+				return "", 0, false, usageError
+			}
+			index++
+			depth, err := strconv.Atoi(arguments[index])
+			if err != nil || depth < 1 {
+				return "", 0, false, fmt.Errorf("\n\rThe depth '%s' has to be a number greater than 0!", arguments[index])
+			}
+			maxDepth = depth
+		case !isPathSet && !strings.HasPrefix(argument, "-") && nodePathRegex.MatchString(argument):
+			nodePath = argument
+			isPathSet = true
+		default:
+			return "", 0, false, usageError
+		}
+	}
+
+	return nodePath, maxDepth, withNotes, nil
+}
+
+func (ts *TreeStrategy) Run() error {
+	nodePath, maxDepth, withNotes, err := parseTreeStatement(ts.statement)
+	if err != nil {
+		return err
+	}
+
+	node, err := resolveNode(ts.mmf, nodePath)
+	if err != nil {
+		return err
+	}
+	for _, line := range ts.mmf.Tree(node, maxDepth, withNotes) {
+		if line.IsActive {
+			fmt.Printf("\n\r%s%s%s%s", line.Prefix, highlightStart, line.Label, highlightEnd)
+			continue
+		}
+		fmt.Printf("\n\r%s%s", line.Prefix, line.Label)
+	}
+
+	return nil
+}
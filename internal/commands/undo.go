@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+)
+
+type UndoStrategy struct {
+	mmf *structure.MetadataNoteWolfyFileHandle
+}
+
+func (us *UndoStrategy) Run() error {
+	entry, err := us.mmf.Undo()
+	if err != nil {
+		return fmt.Errorf("\n\rCould not undo, %v!", err)
+	}
+	if err := us.mmf.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rUndid '%s'!", entry.Description)
+
+	return nil
+}
+
+type RedoStrategy struct {
+	mmf *structure.MetadataNoteWolfyFileHandle
+}
+
+func (rs *RedoStrategy) Run() error {
+	entry, err := rs.mmf.Redo()
+	if err != nil {
+		return fmt.Errorf("\n\rCould not redo, %v!", err)
+	}
+	if err := rs.mmf.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("\n\rRedid '%s'!", entry.Description)
+
+	return nil
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 
 	"github.com/RaphSku/notewolfy/internal/structure"
@@ -19,7 +20,7 @@ func (cws *CreateWorkspaceStrategy) Run() error {
 	pathCaptureGroupName := "path"
 	workspaceNamePattern := "[\\w]+"
 	workspacePathPattern := "[~.]{0,1}/{0,1}.*"
-	pattern := fmt.Sprintf("create workspace (?P<%s>%s) (?P<%s>%s)", nameCaptureGroupName, workspaceNamePattern, pathCaptureGroupName, workspacePathPattern)
+	pattern := fmt.Sprintf("create workspace (?P<%s>%s)(?: (?P<%s>%s))?", nameCaptureGroupName, workspaceNamePattern, pathCaptureGroupName, workspacePathPattern)
 	regex := regexp.MustCompile(pattern)
 	matches := regex.FindStringSubmatch(cws.statement)
 	if len(matches) != 3 {
@@ -36,6 +37,12 @@ func (cws *CreateWorkspaceStrategy) Run() error {
 		}
 	}
 
+	if workspacePath == "" {
+		if cws.mmf.Config.DefaultWorkspaceRoot == "" {
+			return fmt.Errorf("\n\rPlease specify a workspace path or configure a default_workspace_root in your config file!")
+		}
+		workspacePath = filepath.Join(cws.mmf.Config.DefaultWorkspaceRoot, workspaceName)
+	}
 	pathToWorkspace, err := utility.ExpandRelativePaths(workspacePath)
 	if err != nil {
 		return err
@@ -48,15 +55,22 @@ func (cws *CreateWorkspaceStrategy) Run() error {
 		}
 	}
 
-	cws.mmf.AddNewWorkspace(workspaceName, pathToWorkspace)
-	cws.mmf.Save()
-
+	entry := cws.mmf.BeginJournalEntry(cws.statement)
+	entry.TrackFile(pathToWorkspace)
+	entry.TrackPosition()
 	err = os.Mkdir(pathToWorkspace, 0755)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	workspace := structure.NewNode(workspaceName, pathToWorkspace)
+	entry.TrackNode(workspace.ID)
+	cws.mmf.AddWorkspace(workspace)
+	if err := cws.mmf.Save(); err != nil {
+		return err
+	}
+
+	return cws.mmf.CommitJournalEntry(entry)
 }
 
 type DeleteWorkspaceStrategy struct {
@@ -65,19 +79,23 @@ type DeleteWorkspaceStrategy struct {
 }
 
 func (dws *DeleteWorkspaceStrategy) Run() error {
+	flagCaptureGroupName := "flag"
 	nameCaptureGroupName := "name"
 	workspaceNamePattern := "[\\w]+"
-	pattern := fmt.Sprintf("delete workspace (?P<%s>%s)", nameCaptureGroupName, workspaceNamePattern)
+	pattern := fmt.Sprintf("delete workspace (?:(?P<%s>--purge) )?(?P<%s>%s)", flagCaptureGroupName, nameCaptureGroupName, workspaceNamePattern)
 	workspaceNameRegex := regexp.MustCompile(pattern)
 	matches := workspaceNameRegex.FindStringSubmatch(dws.statement)
-	if len(matches) != 2 {
+	if len(matches) != 3 {
 		return fmt.Errorf("\n\rPlease check whether the workspace name matches the regex %s!", workspaceNamePattern)
 	}
 	names := workspaceNameRegex.SubexpNames()
 	var workspaceName string
+	purge := false
 	for i, name := range names[1:] {
 		if name == nameCaptureGroupName {
 			workspaceName = matches[i+1]
+		} else if name == flagCaptureGroupName {
+			purge = matches[i+1] != ""
 		}
 	}
 
@@ -91,27 +109,109 @@ func (dws *DeleteWorkspaceStrategy) Run() error {
 		return fmt.Errorf("\n\rWorkspace '%s' could not be found!", workspaceName)
 	}
 
-	if len(dws.mmf.Workspaces[foundIndex].Children) != 0 || len(dws.mmf.Workspaces[foundIndex].Markdowns) != 0 {
-		return fmt.Errorf("\n\rBefore you delete a workspace, ensure that you have deleted all nodes and markdown files in this workspace!")
+	if !purge && (len(dws.mmf.Workspaces[foundIndex].Children) != 0 || len(dws.mmf.Workspaces[foundIndex].Markdowns) != 0) {
+		return fmt.Errorf("\n\rBefore you delete a workspace, ensure that you have deleted all nodes and markdown files in this workspace or use 'delete workspace --purge %s'!", workspaceName)
+	}
+	if purge {
+		confirmed, err := confirmDeletion(fmt.Sprintf("workspace '%s'", workspaceName), dws.mmf.Workspaces[foundIndex])
+		if err != nil || !confirmed {
+			return err
+		}
 	}
 
 	workspacePath := dws.mmf.Workspaces[foundIndex].Path
-
-	dws.mmf.Workspaces = append(dws.mmf.Workspaces[:foundIndex], dws.mmf.Workspaces[foundIndex+1:]...)
+	entry := dws.mmf.BeginJournalEntry(dws.statement)
+	entry.TrackPosition()
+	err := dws.mmf.MoveWorkspaceToTrash(entry, dws.mmf.Workspaces[foundIndex])
+	if err != nil {
+		return fmt.Errorf("\n\rThe workspace '%s' could not be moved to the trash, the workspace path %s is unchanged, error: %v", workspaceName, workspacePath, err)
+	}
 	if len(dws.mmf.Workspaces) != 0 {
 		dws.mmf.ActiveWorkspace = dws.mmf.Workspaces[0].Name
-		dws.mmf.ActiveNode = dws.mmf.Workspaces[0].Name
+		dws.mmf.ActiveNode = dws.mmf.Workspaces[0].ID
 	} else {
 		dws.mmf.ActiveWorkspace = ""
 		dws.mmf.ActiveNode = ""
 	}
-	dws.mmf.Save()
-
-	err := os.Remove(workspacePath)
+	dws.mmf.PreviousNode = ""
+	err = dws.mmf.Save()
 	if err != nil {
-		return fmt.Errorf("\n\rThe workspace '%s' could not be deleted, please clean up the following workspace path yourself: %s, error: %v", workspaceName, workspacePath, err)
+		return err
 	}
 	fmt.Printf("\n\rDeleted workspace '%s' successfully!", workspaceName)
 
+	return dws.mmf.CommitJournalEntry(entry)
+}
+
+type AdoptWorkspaceStrategy struct {
+	statement string
+	mmf       *structure.MetadataNoteWolfyFileHandle
+}
+
+func (aws *AdoptWorkspaceStrategy) Run() error {
+	nameCaptureGroupName := "name"
+	pathCaptureGroupName := "path"
+	workspaceNamePattern := "[\\w]+"
+	workspacePathPattern := "[~.]{0,1}/{0,1}.*"
+	pattern := fmt.Sprintf("adopt workspace (?P<%s>%s) (?P<%s>%s)", nameCaptureGroupName, workspaceNamePattern, pathCaptureGroupName, workspacePathPattern)
+	regex := regexp.MustCompile(pattern)
+	matches := regex.FindStringSubmatch(aws.statement)
+	if len(matches) != 3 {
+		return fmt.Errorf("\n\rPlease check whether the workspace name matches the regex %s and whether the workspace path matches the regex %s!", workspaceNamePattern, workspacePathPattern)
+	}
+	names := regex.SubexpNames()
+	var workspaceName string
+	var workspacePath string
+	for i, name := range names[1:] {
+		if name == nameCaptureGroupName {
+			workspaceName = matches[i+1]
+		} else if name == pathCaptureGroupName {
+			workspacePath = matches[i+1]
+		}
+	}
+
+	if aws.mmf.DoesWorkspaceExist(workspaceName) {
+		return fmt.Errorf("\n\rThe workspace '%s' already exists, please choose another name!", workspaceName)
+	}
+	pathToWorkspace, err := utility.ExpandRelativePaths(workspacePath)
+	if err != nil {
+		return err
+	}
+	fileInfo, err := os.Stat(pathToWorkspace)
+	if err != nil || !fileInfo.IsDir() {
+		return fmt.Errorf("\n\rThe specified path %s is not an existing directory, use 'create workspace' to create a new workspace!", pathToWorkspace)
+	}
+	for _, workspace := range aws.mmf.Workspaces {
+		if workspace.Path == pathToWorkspace {
+			return fmt.Errorf("\n\rThe directory %s is already registered as workspace '%s'!", pathToWorkspace, workspace.Name)
+		}
+	}
+	if _, err := os.Stat(structure.WorkspaceTreeFilePath(pathToWorkspace)); err == nil {
+		return fmt.Errorf("\n\rThe directory %s already contains a notewolfy workspace, use 'open %s' to register it!", pathToWorkspace, workspacePath)
+	}
+
+	workspace := structure.NewNode(workspaceName, pathToWorkspace)
+	entry := aws.mmf.BeginJournalEntry(aws.statement)
+	entry.TrackNode(workspace.ID)
+	entry.TrackPosition()
+	skippedPaths, err := aws.mmf.AdoptWorkspace(workspace)
+	if err != nil {
+		return err
+	}
+	err = aws.mmf.Save()
+	if err != nil {
+		return err
+	}
+	err = aws.mmf.CommitJournalEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	nodeCount, markdownCount := workspace.CountDescendants()
+	fmt.Printf("\n\rAdopted workspace '%s' with %d nodes and %d markdown files!", workspaceName, nodeCount, markdownCount)
+	for _, path := range skippedPaths {
+		fmt.Printf("\n\rSkipped %s, its name does not match the regex %s!", path, namePattern)
+	}
+
 	return nil
 }
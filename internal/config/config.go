@@ -0,0 +1,218 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/RaphSku/notewolfy/internal/utility"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	NOTEWOLFY_HOME    = "NOTEWOLFY_HOME"
+	NOTEWOLFY_CONFIG  = "NOTEWOLFY_CONFIG"
+	NOTEWOLFY_BACKEND = "NOTEWOLFY_BACKEND"
+	VISUAL            = "VISUAL"
+	EDITOR            = "EDITOR"
+
+	ConfigFileName   = "config.yaml"
+	AppDirName       = "notewolfy"
+	DefaultEditor    = "vim"
+	DefaultTrashAge  = "30d"
+	legacyFileName   = ".notewolfy"
+	metadataBaseName = "metadata"
+)
+
+// Overrides carry the values of the CLI flags, they take precedence over the environment and the config file.
+type Overrides struct {
+	ConfigPath string
+	Backend    string
+}
+
+type FileConfig struct {
+	Backend              string            `yaml:"backend"`
+	DataDir              string            `yaml:"data_dir"`
+	Editor               string            `yaml:"editor"`
+	Editors              map[string]string `yaml:"editors"`
+	DefaultWorkspaceRoot string            `yaml:"default_workspace_root"`
+	TrashMaxAge          string            `yaml:"trash_max_age"`
+}
+
+// ConfigFilePath resolves the config file in the order: --config flag, NOTEWOLFY_CONFIG,
+// $NOTEWOLFY_HOME/config.yaml and $XDG_CONFIG_HOME/notewolfy/config.yaml.
+func ConfigFilePath(overrides Overrides) (string, error) {
+	if overrides.ConfigPath != "" {
+		return utility.ExpandRelativePaths(overrides.ConfigPath)
+	}
+	if configPath := os.Getenv(NOTEWOLFY_CONFIG); configPath != "" {
+		return utility.ExpandRelativePaths(configPath)
+	}
+	if home := os.Getenv(NOTEWOLFY_HOME); home != "" {
+		expandedHome, err := utility.ExpandRelativePaths(home)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(expandedHome, ConfigFileName), nil
+	}
+	configHome, err := xdgDir("XDG_CONFIG_HOME", ".config")
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configHome, AppDirName, ConfigFileName), nil
+}
+
+func xdgDir(variable string, fallback string) (string, error) {
+	if dir := os.Getenv(variable); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	homeDir, err := utility.GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, fallback), nil
+}
+
+// ReadFileConfig returns an empty config if the file does not exist.
+func ReadFileConfig(configPath string) (*FileConfig, error) {
+	fileConfig := &FileConfig{}
+	content, err := os.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fileConfig, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(content, fileConfig); err != nil {
+		return nil, fmt.Errorf("config file %s is invalid: %w", configPath, err)
+	}
+
+	return fileConfig, nil
+}
+
+// Load resolves the complete configuration of notewolfy, the metadata lives in the data directory, which is
+// $NOTEWOLFY_HOME, the data_dir of the config file or $XDG_DATA_HOME/notewolfy. An existing ~/.notewolfy is kept
+// in use as long as no data directory has been configured explicitly.
+func Load(overrides Overrides) (*structure.Config, error) {
+	configPath, err := ConfigFilePath(overrides)
+	if err != nil {
+		return nil, err
+	}
+	fileConfig, err := ReadFileConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := fileConfig.Backend
+	if envBackend := os.Getenv(NOTEWOLFY_BACKEND); envBackend != "" {
+		backend = envBackend
+	}
+	if overrides.Backend != "" {
+		backend = overrides.Backend
+	}
+	if backend == "" {
+		backend = structure.JSONBackend
+	}
+	if backend != structure.JSONBackend && backend != structure.BoltBackend {
+		return nil, fmt.Errorf("unknown metadata backend '%s', supported backends are '%s' and '%s'", backend, structure.JSONBackend, structure.BoltBackend)
+	}
+
+	metadataFilePath, err := metadataFilePath(fileConfig, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	// The editor of the config file is meant for notewolfy only, which is why it wins over $VISUAL and $EDITOR.
+	editor := fileConfig.Editor
+	for _, variable := range []string{VISUAL, EDITOR} {
+		if editor == "" {
+			editor = os.Getenv(variable)
+		}
+	}
+	if editor == "" {
+		editor = DefaultEditor
+	}
+	var editors map[string]string
+	for extension, extensionEditor := range fileConfig.Editors {
+		if editors == nil {
+			editors = make(map[string]string)
+		}
+		editors["."+strings.ToLower(strings.TrimPrefix(extension, "."))] = extensionEditor
+	}
+	defaultWorkspaceRoot := fileConfig.DefaultWorkspaceRoot
+	if defaultWorkspaceRoot != "" {
+		defaultWorkspaceRoot, err = utility.ExpandRelativePaths(defaultWorkspaceRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	trashMaxAge := fileConfig.TrashMaxAge
+	if trashMaxAge == "" {
+		trashMaxAge = DefaultTrashAge
+	}
+	trashMaxAgeDuration, err := utility.ParseAge(trashMaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("trash_max_age of config file %s is invalid: %w", configPath, err)
+	}
+
+	return &structure.Config{
+		MetadataFilePath:     metadataFilePath,
+		Backend:              backend,
+		Editor:               editor,
+		Editors:              editors,
+		DefaultWorkspaceRoot: defaultWorkspaceRoot,
+		TrashMaxAge:          trashMaxAgeDuration,
+	}, nil
+}
+
+func metadataFilePath(fileConfig *FileConfig, backend string) (string, error) {
+	extension := ".json"
+	if backend == structure.BoltBackend {
+		extension = ".db"
+	}
+
+	dataDir := ""
+	if home := os.Getenv(NOTEWOLFY_HOME); home != "" {
+		dataDir = home
+	} else if fileConfig.DataDir != "" {
+		dataDir = fileConfig.DataDir
+	}
+	if dataDir != "" {
+		expandedDataDir, err := utility.ExpandRelativePaths(dataDir)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(expandedDataDir, metadataBaseName+extension), ensureDir(expandedDataDir)
+	}
+
+	dataHome, err := xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+	if err != nil {
+		return "", err
+	}
+	xdgMetadataFilePath := filepath.Join(dataHome, AppDirName, metadataBaseName+extension)
+	if _, err := os.Stat(xdgMetadataFilePath); errors.Is(err, os.ErrNotExist) {
+		homeDir, err := utility.GetHomeDir()
+		if err != nil {
+			return "", err
+		}
+		legacyFilePath := filepath.Join(homeDir, legacyFileName)
+		if backend == structure.BoltBackend {
+			legacyFilePath += ".db"
+		}
+		if fileInfo, err := os.Stat(legacyFilePath); err == nil && fileInfo.Mode().IsRegular() {
+			return legacyFilePath, nil
+		}
+	}
+
+	return xdgMetadataFilePath, ensureDir(filepath.Dir(xdgMetadataFilePath))
+}
+
+func ensureDir(dir string) error {
+	return os.MkdirAll(dir, 0755)
+}
@@ -0,0 +1,141 @@
+//go:build unit_test
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RaphSku/notewolfy/internal/config"
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupEnvironment(t *testing.T) string {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv(config.NOTEWOLFY_HOME, "")
+	t.Setenv(config.NOTEWOLFY_CONFIG, "")
+	t.Setenv(config.NOTEWOLFY_BACKEND, "")
+	t.Setenv(config.VISUAL, "")
+	t.Setenv(config.EDITOR, "")
+
+	return homeDir
+}
+
+func writeConfigFile(t *testing.T, path string, content string) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile(path, []byte(content), 0644)
+	assert.NoError(t, err)
+}
+
+func TestLoadDefaults(t *testing.T) {
+	homeDir := setupEnvironment(t)
+
+	actConfig, err := config.Load(config.Overrides{})
+	assert.NoError(t, err)
+	expConfig := &structure.Config{
+		MetadataFilePath: filepath.Join(homeDir, ".local", "share", "notewolfy", "metadata.json"),
+		Backend:          structure.JSONBackend,
+		Editor:           config.DefaultEditor,
+		TrashMaxAge:      30 * 24 * time.Hour,
+	}
+	assert.Equal(t, expConfig, actConfig)
+}
+
+func TestLoadKeepsLegacyMetadataFile(t *testing.T) {
+	homeDir := setupEnvironment(t)
+	writeConfigFile(t, filepath.Join(homeDir, ".notewolfy"), "{}")
+
+	actConfig, err := config.Load(config.Overrides{})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(homeDir, ".notewolfy"), actConfig.MetadataFilePath)
+}
+
+func TestLoadFromXDGConfigFile(t *testing.T) {
+	homeDir := setupEnvironment(t)
+	configHome := filepath.Join(homeDir, "xdg-config")
+	dataHome := filepath.Join(homeDir, "xdg-data")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	writeConfigFile(t, filepath.Join(configHome, "notewolfy", "config.yaml"), "backend: bolt\neditor: nano\neditors:\n  txt: code --wait\n  .MD: hx\ndefault_workspace_root: ~/notes\ntrash_max_age: 12h\n")
+
+	actConfig, err := config.Load(config.Overrides{})
+	assert.NoError(t, err)
+	expConfig := &structure.Config{
+		MetadataFilePath:     filepath.Join(dataHome, "notewolfy", "metadata.db"),
+		Backend:              structure.BoltBackend,
+		Editor:               "nano",
+		Editors:              map[string]string{".txt": "code --wait", ".md": "hx"},
+		DefaultWorkspaceRoot: filepath.Join(homeDir, "notes"),
+		TrashMaxAge:          12 * time.Hour,
+	}
+	assert.Equal(t, expConfig, actConfig)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	homeDir := setupEnvironment(t)
+	profileDir := filepath.Join(homeDir, "work")
+	t.Setenv(config.NOTEWOLFY_HOME, profileDir)
+	writeConfigFile(t, filepath.Join(profileDir, "config.yaml"), "backend: bolt\neditor: nano\n")
+	flagConfigPath := filepath.Join(homeDir, "flag.yaml")
+	writeConfigFile(t, flagConfigPath, "editor: emacs\n")
+
+	actConfig, err := config.Load(config.Overrides{})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(profileDir, "metadata.db"), actConfig.MetadataFilePath)
+	assert.Equal(t, "nano", actConfig.Editor)
+
+	t.Setenv(config.NOTEWOLFY_BACKEND, structure.JSONBackend)
+	actConfig, err = config.Load(config.Overrides{})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(profileDir, "metadata.json"), actConfig.MetadataFilePath)
+
+	actConfig, err = config.Load(config.Overrides{ConfigPath: flagConfigPath, Backend: structure.BoltBackend})
+	assert.NoError(t, err)
+	assert.Equal(t, "emacs", actConfig.Editor)
+	assert.Equal(t, structure.BoltBackend, actConfig.Backend)
+	assert.Equal(t, filepath.Join(profileDir, "metadata.db"), actConfig.MetadataFilePath)
+}
+
+func TestLoadEditorFromEnvironment(t *testing.T) {
+	homeDir := setupEnvironment(t)
+	t.Setenv(config.EDITOR, "nano")
+
+	actConfig, err := config.Load(config.Overrides{})
+	assert.NoError(t, err)
+	assert.Equal(t, "nano", actConfig.Editor)
+
+	t.Setenv(config.VISUAL, "code --wait")
+	actConfig, err = config.Load(config.Overrides{})
+	assert.NoError(t, err)
+	assert.Equal(t, "code --wait", actConfig.Editor)
+
+	configPath := filepath.Join(homeDir, "config.yaml")
+	writeConfigFile(t, configPath, "editor: hx\n")
+	actConfig, err = config.Load(config.Overrides{ConfigPath: configPath})
+	assert.NoError(t, err)
+	assert.Equal(t, "hx", actConfig.Editor)
+}
+
+func TestLoadWithInvalidConfigFile(t *testing.T) {
+	homeDir := setupEnvironment(t)
+	configPath := filepath.Join(homeDir, "config.yaml")
+	writeConfigFile(t, configPath, "editor: [vim\n")
+
+	_, err := config.Load(config.Overrides{ConfigPath: configPath})
+	assert.ErrorContains(t, err, "config file "+configPath+" is invalid")
+
+	writeConfigFile(t, configPath, "backend: sqlite\n")
+	_, err = config.Load(config.Overrides{ConfigPath: configPath})
+	assert.EqualError(t, err, "unknown metadata backend 'sqlite', supported backends are 'json' and 'bolt'")
+
+	writeConfigFile(t, configPath, "trash_max_age: forever\n")
+	_, err = config.Load(config.Overrides{ConfigPath: configPath})
+	assert.ErrorContains(t, err, "trash_max_age of config file "+configPath+" is invalid")
+}
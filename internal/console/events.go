@@ -2,12 +2,11 @@ package console
 
 import (
 	"fmt"
-	"path/filepath"
 
 	"github.com/RaphSku/cyclecmd"
 	"github.com/RaphSku/notewolfy/internal/commands"
+	"github.com/RaphSku/notewolfy/internal/config"
 	"github.com/RaphSku/notewolfy/internal/structure"
-	"github.com/RaphSku/notewolfy/internal/utility"
 )
 
 const NOTEWOLFY_GOODBYE_MESSAGE = "\r\nThank you for using notewolfy!\r\n"
@@ -48,18 +47,20 @@ func (be *BackspaceEvent) Handle(token string) (error, *cyclecmd.ControlEvent) {
 }
 
 var mmf *structure.MetadataNoteWolfyFileHandle
+var configOverrides config.Overrides
+
+// Configure sets the overrides from the CLI flags, it has to be called before the metadata is initialised.
+func Configure(overrides config.Overrides) {
+	configOverrides = overrides
+}
 
 func InitMetadataNoteWolfyFileHandle() error {
 	if mmf == nil {
-		homeDir, err := utility.GetHomeDir()
+		notewolfyConfig, err := config.Load(configOverrides)
 		if err != nil {
 			return err
 		}
-		metadataFilePath := filepath.Join(homeDir, ".notewolfy")
-		config := &structure.Config{
-			MetadataFilePath: metadataFilePath,
-		}
-		newmmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+		newmmf, err := structure.NewMetadataNoteWolfyFileHandle(notewolfyConfig)
 		if err != nil {
 			return err
 		}
@@ -68,6 +69,13 @@ func InitMetadataNoteWolfyFileHandle() error {
 	return nil
 }
 
+func GetMetadataNoteWolfyFileHandle() (*structure.MetadataNoteWolfyFileHandle, error) {
+	if err := InitMetadataNoteWolfyFileHandle(); err != nil {
+		return nil, err
+	}
+	return mmf, nil
+}
+
 type EnterEvent struct{}
 
 func (ee *EnterEvent) Handle(token string) (error, *cyclecmd.ControlEvent) {
@@ -0,0 +1,343 @@
+package structure
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+var (
+	markdownLinkRegex = regexp.MustCompile(`!?\[[^\]\[]*\]\((?P<target><[^>]*>|[^()\s]+)(?:\s+"[^"]*")?\)`)
+	urlSchemeRegex    = regexp.MustCompile(`^(?:[a-zA-Z][a-zA-Z0-9+.-]*:|//)`)
+	headingRegex      = regexp.MustCompile(`^#{1,6}\s+(?P<heading>.*?)(?:\s+#+)?\s*$`)
+)
+
+// BrokenLink is a link of a note whose target does not exist, the suggestion is a fixed link if a similar target exists.
+type BrokenLink struct {
+	Note       *NoteReference
+	Line       int
+	Link       string
+	Problem    string
+	Suggestion string
+}
+
+type markdownLink struct {
+	raw    string
+	target string
+	line   int
+}
+
+// parseMarkdownLinks returns the [text](target) links of the content, links inside of code are skipped.
+func parseMarkdownLinks(content []byte) []*markdownLink {
+	var links []*markdownLink
+	lines := proseLines(content)
+	for _, lineNumber := range sortedLineNumbers(lines) {
+		for _, matches := range markdownLinkRegex.FindAllStringSubmatch(lines[lineNumber], -1) {
+			target := strings.TrimSuffix(strings.TrimPrefix(matches[markdownLinkRegex.SubexpIndex("target")], "<"), ">")
+			links = append(links, &markdownLink{raw: matches[0], target: target, line: lineNumber})
+		}
+	}
+
+	return links
+}
+
+// splitLinkTarget returns the unescaped path and the anchor of a link target, URLs are not local.
+func splitLinkTarget(target string) (string, string, bool, bool) {
+	if urlSchemeRegex.MatchString(target) {
+		return "", "", false, false
+	}
+	linkPath, anchor, hasAnchor := strings.Cut(target, "#")
+	if unescapedPath, err := url.PathUnescape(linkPath); err == nil {
+		linkPath = unescapedPath
+	}
+
+	return linkPath, anchor, hasAnchor, true
+}
+
+// linkFilePath returns the file that the link path of the note points to, an absolute link starts at the directory of the workspace.
+func linkFilePath(workspace *Node, note *NoteReference, linkPath string) string {
+	if strings.HasPrefix(linkPath, "/") {
+		return filepath.Join(workspace.Path, filepath.FromSlash(linkPath))
+	}
+
+	return filepath.Join(note.Node.Path, filepath.FromSlash(linkPath))
+}
+
+// headingSlug turns a heading into its anchor like GitHub does, the text is lowercased,
+// punctuation is dropped and spaces become dashes.
+func headingSlug(heading string) string {
+	var slug strings.Builder
+	for _, character := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(character) || unicode.IsDigit(character) || character == '-' || character == '_':
+			slug.WriteRune(character)
+		case character == ' ':
+			slug.WriteRune('-')
+		}
+	}
+
+	return slug.String()
+}
+
+// headingSlugs returns the anchors of all headings of the note, repeated headings get a numbered suffix.
+func headingSlugs(content []byte) []string {
+	_, body := SplitFrontMatter(content)
+	lines := proseLines(body)
+	var slugs []string
+	slugCounts := make(map[string]int)
+	for _, lineNumber := range sortedLineNumbers(lines) {
+		matches := headingRegex.FindStringSubmatch(lines[lineNumber])
+		if matches == nil {
+			continue
+		}
+		slug := headingSlug(matches[headingRegex.SubexpIndex("heading")])
+		if count := slugCounts[slug]; count > 0 {
+			slugCounts[slug]++
+			slug = fmt.Sprintf("%s-%d", slug, count)
+		} else {
+			slugCounts[slug]++
+		}
+		slugs = append(slugs, slug)
+	}
+
+	return slugs
+}
+
+func levenshteinDistance(a string, b string) int {
+	aRunes, bRunes := []rune(a), []rune(b)
+	previousRow := make([]int, len(bRunes)+1)
+	for index := range previousRow {
+		previousRow[index] = index
+	}
+	for i := 1; i <= len(aRunes); i++ {
+		currentRow := make([]int, len(bRunes)+1)
+		currentRow[0] = i
+		for j := 1; j <= len(bRunes); j++ {
+			substitutionCost := 1
+			if aRunes[i-1] == bRunes[j-1] {
+				substitutionCost = 0
+			}
+			currentRow[j] = min(previousRow[j]+1, currentRow[j-1]+1, previousRow[j-1]+substitutionCost)
+		}
+		previousRow = currentRow
+	}
+
+	return previousRow[len(bRunes)]
+}
+
+// nearestCandidate returns the index of the candidate closest to the name, -1 if none is close enough to be a likely typo.
+func nearestCandidate(name string, candidates []string) int {
+	name = strings.ToLower(name)
+	nearestIndex, nearestDistance := -1, max(2, len([]rune(name))/2)+1
+	for index, candidate := range candidates {
+		if distance := levenshteinDistance(name, strings.ToLower(candidate)); distance < nearestDistance {
+			nearestIndex, nearestDistance = index, distance
+		}
+	}
+
+	return nearestIndex
+}
+
+type linkChecker struct {
+	workspace *Node
+	notes     []*NoteReference
+	names     []string
+	slugs     map[string][]string
+}
+
+func newLinkChecker(workspace *Node) *linkChecker {
+	notes := workspaceNotes(workspace)
+	var names []string
+	for _, note := range notes {
+		names = append(names, strings.TrimSuffix(note.Markdown.Filename, MarkdownFileExtension))
+	}
+
+	return &linkChecker{workspace: workspace, notes: notes, names: names, slugs: make(map[string][]string)}
+}
+
+func (lc *linkChecker) headingSlugs(path string) ([]string, error) {
+	if slugs, isRead := lc.slugs[path]; isRead {
+		return slugs, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lc.slugs[path] = headingSlugs(content)
+
+	return lc.slugs[path], nil
+}
+
+// checkAnchor returns a problem and a suggested anchor if the note at the path has no heading with this anchor.
+func (lc *linkChecker) checkAnchor(path string, anchor string) (string, string, error) {
+	slugs, err := lc.headingSlugs(path)
+	if err != nil {
+		return "", "", err
+	}
+	if slices.Contains(slugs, anchor) {
+		return "", "", nil
+	}
+	suggestion := ""
+	if index := nearestCandidate(anchor, slugs); index != -1 {
+		suggestion = slugs[index]
+	}
+
+	return "points to a heading that does not exist", suggestion, nil
+}
+
+func (lc *linkChecker) checkMarkdownLink(note *NoteReference, target string) (string, string, error) {
+	linkPath, anchor, hasAnchor, isLocal := splitLinkTarget(target)
+	if !isLocal {
+		return "", "", nil
+	}
+
+	filePath := note.FilePath()
+	if linkPath != "" {
+		filePath = linkFilePath(lc.workspace, note, linkPath)
+		if _, err := os.Stat(filePath); errors.Is(err, os.ErrNotExist) {
+			suggestion := ""
+			name := strings.TrimSuffix(filepath.Base(linkPath), MarkdownFileExtension)
+			if index := nearestCandidate(name, lc.names); index != -1 {
+				if relativePath, err := filepath.Rel(note.Node.Path, lc.notes[index].FilePath()); err == nil {
+					suggestion = filepath.ToSlash(relativePath)
+					if hasAnchor {
+						suggestion += "#" + anchor
+					}
+				}
+			}
+			return "points to a file that does not exist", suggestion, nil
+		} else if err != nil {
+			return "", "", err
+		}
+	}
+	if !hasAnchor || anchor == "" || filepath.Ext(filePath) != MarkdownFileExtension {
+		return "", "", nil
+	}
+
+	problem, suggestedAnchor, err := lc.checkAnchor(filePath, strings.ToLower(anchor))
+	if problem == "" || err != nil {
+		return "", "", err
+	}
+	suggestion := ""
+	if suggestedAnchor != "" {
+		suggestion = linkPath + "#" + suggestedAnchor
+	}
+
+	return problem, suggestion, nil
+}
+
+// wikiLinkTarget returns the shortest target that resolves to the note, the name if it is unique in the workspace.
+func (lc *linkChecker) wikiLinkTarget(note *NoteReference) string {
+	name := strings.TrimSuffix(note.Markdown.Filename, MarkdownFileExtension)
+	count := 0
+	for _, otherNote := range lc.notes {
+		if otherNote.Markdown.Filename == note.Markdown.Filename {
+			count++
+		}
+	}
+	if count == 1 {
+		return name
+	}
+
+	return joinNodePath(note.NodePath, name)
+}
+
+func (lc *linkChecker) checkWikiLink(note *NoteReference, link *WikiLink) (string, string, error) {
+	linkedNote := ResolveWikiLink(lc.notes, note, link.Target)
+	if linkedNote == nil {
+		suggestion := ""
+		_, name := SplitNodePath(link.Target)
+		if index := nearestCandidate(name, lc.names); index != -1 {
+			suggestion = lc.wikiLinkTarget(lc.notes[index])
+			if link.Anchor != "" {
+				suggestion += "#" + link.Anchor
+			}
+			suggestion = "[[" + suggestion + "]]"
+		}
+		return "points to a note that does not exist", suggestion, nil
+	}
+	if link.Anchor == "" {
+		return "", "", nil
+	}
+
+	problem, suggestedAnchor, err := lc.checkAnchor(linkedNote.FilePath(), headingSlug(link.Anchor))
+	if problem == "" || err != nil {
+		return "", "", err
+	}
+	suggestion := ""
+	if suggestedAnchor != "" {
+		suggestion = "[[" + link.Target + "#" + suggestedAnchor + "]]"
+	}
+
+	return problem, suggestion, nil
+}
+
+func (lc *linkChecker) checkNote(note *NoteReference) ([]*BrokenLink, error) {
+	content, err := os.ReadFile(note.FilePath())
+	if errors.Is(err, os.ErrNotExist) {
+		// Missing notes are reported by sync, not by the link checker.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, body := SplitFrontMatter(content)
+	lineOffset := strings.Count(string(content[:len(content)-len(body)]), "\n")
+
+	var brokenLinks []*BrokenLink
+	for _, link := range parseMarkdownLinks(body) {
+		problem, suggestion, err := lc.checkMarkdownLink(note, link.target)
+		if err != nil {
+			return nil, err
+		}
+		if problem != "" {
+			brokenLinks = append(brokenLinks, &BrokenLink{Note: note, Line: link.line + lineOffset, Link: link.raw, Problem: problem, Suggestion: suggestion})
+		}
+	}
+	for _, link := range ParseWikiLinks(body) {
+		problem, suggestion, err := lc.checkWikiLink(note, link)
+		if err != nil {
+			return nil, err
+		}
+		if problem == "" {
+			continue
+		}
+		rawLink := link.Target
+		if link.Anchor != "" {
+			rawLink += "#" + link.Anchor
+		}
+		brokenLinks = append(brokenLinks, &BrokenLink{Note: note, Line: link.Line + lineOffset, Link: "[[" + rawLink + "]]", Problem: problem, Suggestion: suggestion})
+	}
+	slices.SortStableFunc(brokenLinks, func(a *BrokenLink, b *BrokenLink) int {
+		return a.Line - b.Line
+	})
+
+	return brokenLinks, nil
+}
+
+// CheckLinks returns the broken markdown and wiki links of all notes of the active workspace.
+// Relative links are checked against the filesystem and anchors against the headings of the linked note.
+func (mmf *MetadataNoteWolfyFileHandle) CheckLinks() ([]*BrokenLink, error) {
+	activeWorkspace := mmf.FindActiveWorkspace()
+	if activeWorkspace == nil {
+		return nil, errors.New("no active workspace, seems like you have not created a workspace yet")
+	}
+
+	checker := newLinkChecker(activeWorkspace)
+	var brokenLinks []*BrokenLink
+	for _, note := range checker.notes {
+		noteBrokenLinks, err := checker.checkNote(note)
+		if err != nil {
+			return nil, fmt.Errorf("could not check the links of '%s': %w", note.Markdown.Filename, err)
+		}
+		brokenLinks = append(brokenLinks, noteBrokenLinks...)
+	}
+
+	return brokenLinks, nil
+}
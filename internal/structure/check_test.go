@@ -0,0 +1,70 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckLinks(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid.New().String())
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	workspacePath := t.TempDir()
+	err = mmf.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	meetings := structure.NewNode("meetings", filepath.Join(workspacePath, "meetings"))
+	err = mmf.AddChild(meetings)
+	assert.NoError(t, err)
+	err = os.MkdirAll(meetings.Path, 0755)
+	assert.NoError(t, err)
+
+	addNote := func(node *structure.Node, filename string, content string) {
+		mmf.AddMarkdownToNode(node, structure.NewMarkdown(filename))
+		err := os.WriteFile(filepath.Join(node.Path, filename), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+	addNote(mmf.Workspaces[0], "groceries.md", "---\ntitle: Groceries\n---\n# Groceries\n## Snacks\n## Snacks\n")
+	addNote(meetings, "weekly.md", "# Agenda\n"+
+		"[valid](../groceries.md#snacks-1) [web](https://example.com) [own](#agenda) [root](/groceries.md)\n"+
+		"[typo](../grocery.md) [anchor](../groceries.md#snaks) [own anchor](#agneda)\n"+
+		"`[code](missing.md)` [[groceries#Snacks]] [[monthy]] [[groceries#drinks]]\n"+
+		"```\n[fenced](missing.md)\n```\n")
+	addNote(meetings, "monthly.md", "---\ntags: [team]\n---\nSee [[weekly]] and [missing](archive/old.md)\n")
+
+	brokenLinks, err := mmf.CheckLinks()
+	assert.NoError(t, err)
+	type brokenLink struct {
+		filename   string
+		line       int
+		link       string
+		problem    string
+		suggestion string
+	}
+	var got []brokenLink
+	for _, link := range brokenLinks {
+		got = append(got, brokenLink{link.Note.Markdown.Filename, link.Line, link.Link, link.Problem, link.Suggestion})
+	}
+	assert.Equal(t, []brokenLink{
+		{"weekly.md", 3, "[typo](../grocery.md)", "points to a file that does not exist", "../groceries.md"},
+		{"weekly.md", 3, "[anchor](../groceries.md#snaks)", "points to a heading that does not exist", "../groceries.md#snacks"},
+		{"weekly.md", 3, "[own anchor](#agneda)", "points to a heading that does not exist", "#agenda"},
+		{"weekly.md", 4, "[[monthy]]", "points to a note that does not exist", "[[monthly]]"},
+		{"weekly.md", 4, "[[groceries#drinks]]", "points to a heading that does not exist", ""},
+		{"monthly.md", 4, "[missing](archive/old.md)", "points to a file that does not exist", ""},
+	}, got)
+}
@@ -0,0 +1,133 @@
+package structure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/RaphSku/notewolfy/internal/utility"
+	"github.com/google/uuid"
+)
+
+const copySuffix = "_copy"
+
+// uniqueName appends _copy, _copy2, ... to the name until it is not taken anymore.
+func uniqueName(name string, isTaken func(name string) bool) string {
+	if !isTaken(name) {
+		return name
+	}
+	candidate := name + copySuffix
+	for index := 2; isTaken(candidate); index++ {
+		candidate = fmt.Sprintf("%s%s%d", name, copySuffix, index)
+	}
+
+	return candidate
+}
+
+func isPathTaken(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func renewIDs(node *Node) {
+	node.ID = uuid.New().String()
+	for _, markdown := range node.Markdowns {
+		markdown.ID = uuid.New().String()
+	}
+	for _, child := range node.Children {
+		renewIDs(child)
+	}
+}
+
+// refreshCopiedMarkdown reads the metadata of a copied note from its new file, the copy is created now and
+// does not inherit the creation time of its source.
+func refreshCopiedMarkdown(markdown *Markdown, path string) error {
+	markdown.Created = time.Time{}
+	_, err := markdown.Refresh(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// The source was missing on disk already, sync reports it.
+		return nil
+	}
+
+	return err
+}
+
+func refreshCopiedNode(node *Node) error {
+	for _, markdown := range node.Markdowns {
+		if err := refreshCopiedMarkdown(markdown, filepath.Join(node.Path, markdown.Filename)); err != nil {
+			return err
+		}
+	}
+	for _, child := range node.Children {
+		if err := refreshCopiedNode(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) copyTrackedPath(entry *JournalEntry, sourcePath string, targetPath string) error {
+	if err := utility.CopyPath(sourcePath, targetPath); err != nil {
+		return err
+	}
+	entry.TrackCopy(sourcePath, targetPath)
+
+	return nil
+}
+
+// CopyMarkdown copies the markdown file to the target node under the new name, a taken name gets a _copy suffix.
+// The metadata of the copy is read from the copied file.
+func (mmf *MetadataNoteWolfyFileHandle) CopyMarkdown(entry *JournalEntry, sourceNode *Node, markdown *Markdown, targetNode *Node, newName string) (*Markdown, error) {
+	newName = uniqueName(newName, func(name string) bool {
+		return mmf.FindMarkdown(targetNode, name) != nil || isPathTaken(filepath.Join(targetNode.Path, name+MarkdownFileExtension))
+	})
+	copiedMarkdown := NewMarkdown(newName + MarkdownFileExtension)
+
+	entry.TrackMarkdown(copiedMarkdown.ID)
+	if err := mmf.copyTrackedPath(entry, filepath.Join(sourceNode.Path, markdown.Filename), filepath.Join(targetNode.Path, copiedMarkdown.Filename)); err != nil {
+		return nil, err
+	}
+	if err := refreshCopiedMarkdown(copiedMarkdown, filepath.Join(targetNode.Path, copiedMarkdown.Filename)); err != nil {
+		return nil, err
+	}
+	mmf.AddMarkdownToNode(targetNode, copiedMarkdown)
+
+	return copiedMarkdown, nil
+}
+
+// CopyNode copies the node with its subtree below the target node, the copies get new IDs.
+func (mmf *MetadataNoteWolfyFileHandle) CopyNode(entry *JournalEntry, node *Node, targetNode *Node) (*Node, error) {
+	if mmf.findParentInAllWorkspaces(node.ID) == nil {
+		return nil, fmt.Errorf("node '%s' is a workspace root and cannot be copied", node.Name)
+	}
+	if targetNode.ID == node.ID || node.Contains(targetNode.ID) {
+		return nil, fmt.Errorf("node '%s' cannot be copied into itself or one of its child nodes", node.Name)
+	}
+	newName := uniqueName(node.Name, func(name string) bool {
+		for _, child := range targetNode.Children {
+			if child.Name == name {
+				return true
+			}
+		}
+		return isPathTaken(filepath.Join(targetNode.Path, name))
+	})
+	copiedNode := node.Clone()
+	copiedNode.Trash = nil
+	renewIDs(copiedNode)
+	copiedNode.Name = newName
+	copiedNode.SetPath(filepath.Join(targetNode.Path, newName))
+
+	entry.TrackNode(copiedNode.ID)
+	if err := mmf.copyTrackedPath(entry, node.Path, copiedNode.Path); err != nil {
+		return nil, err
+	}
+	if err := refreshCopiedNode(copiedNode); err != nil {
+		return nil, err
+	}
+	targetNode.Children = append(targetNode.Children, copiedNode)
+
+	return copiedNode, nil
+}
@@ -0,0 +1,92 @@
+package structure
+
+import (
+	"cmp"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	fuzzyMatchScore       = 16
+	fuzzyConsecutiveBonus = 12
+	fuzzyBoundaryBonus    = 8
+	fuzzyGapPenalty       = 1
+	fuzzyNameBonus        = 16
+	// A note that was modified just now gets the full recency bonus, a note modified a week ago half of it.
+	fuzzyRecencyBonus    = 4.0
+	fuzzyRecencyHalfLife = 7 * 24 * time.Hour
+)
+
+// FoundNote is a note that matched the pattern of find together with its score.
+type FoundNote struct {
+	*NoteReference
+	Score float64
+}
+
+// fuzzyScore matches the characters of the pattern in order against the candidate, ignoring case.
+// Consecutive characters and characters at the start of a word score higher, skipped characters lower the score.
+func fuzzyScore(pattern string, candidate string) (int, bool) {
+	patternRunes := []rune(strings.ToLower(pattern))
+	if len(patternRunes) == 0 {
+		return 0, false
+	}
+
+	score := 0
+	patternIndex := 0
+	previousMatch := -1
+	previousRune := '/'
+	for candidateIndex, candidateRune := range []rune(candidate) {
+		if patternIndex < len(patternRunes) && unicode.ToLower(candidateRune) == patternRunes[patternIndex] {
+			score += fuzzyMatchScore
+			if previousMatch == candidateIndex-1 {
+				score += fuzzyConsecutiveBonus
+			}
+			isWordStart := !unicode.IsLetter(previousRune) && !unicode.IsDigit(previousRune)
+			if isWordStart || (unicode.IsLower(previousRune) && unicode.IsUpper(candidateRune)) {
+				score += fuzzyBoundaryBonus
+			}
+			if previousMatch != -1 {
+				score -= fuzzyGapPenalty * (candidateIndex - previousMatch - 1)
+			}
+			previousMatch = candidateIndex
+			patternIndex++
+		}
+		previousRune = candidateRune
+	}
+	if patternIndex != len(patternRunes) {
+		return 0, false
+	}
+
+	return score, true
+}
+
+// FindNotes fuzzy matches the pattern against the node paths and names of the notes of the active workspace.
+// Matches in the name of a note and recently modified notes rank higher.
+func (mmf *MetadataNoteWolfyFileHandle) FindNotes(pattern string, now time.Time) []*FoundNote {
+	pattern = strings.Join(strings.Fields(pattern), "")
+	var foundNotes []*FoundNote
+	for _, note := range mmf.ActiveWorkspaceNotes() {
+		name := strings.TrimSuffix(note.Markdown.Filename, MarkdownFileExtension)
+		pathScore, isMatch := fuzzyScore(pattern, path.Join(note.NodePath, name))
+		if !isMatch {
+			continue
+		}
+		score := float64(pathScore)
+		if nameScore, isNameMatch := fuzzyScore(pattern, name); isNameMatch {
+			score = max(score, float64(nameScore+fuzzyNameBonus))
+		}
+		if !note.Markdown.Modified.IsZero() {
+			age := max(0, now.Sub(note.Markdown.Modified))
+			score += fuzzyRecencyBonus / (1 + float64(age)/float64(fuzzyRecencyHalfLife))
+		}
+		foundNotes = append(foundNotes, &FoundNote{NoteReference: note, Score: score})
+	}
+	slices.SortStableFunc(foundNotes, func(a *FoundNote, b *FoundNote) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	return foundNotes
+}
@@ -0,0 +1,60 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindNotes(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid.New().String())
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	workspacePath := t.TempDir()
+	err = mmf.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	meetings := structure.NewNode("meetings", filepath.Join(workspacePath, "meetings"))
+	err = mmf.AddChild(meetings)
+	assert.NoError(t, err)
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	addNote := func(node *structure.Node, filename string, age time.Duration) {
+		markdown := structure.NewMarkdown(filename)
+		markdown.Modified = now.Add(-age)
+		mmf.AddMarkdownToNode(node, markdown)
+	}
+	addNote(mmf.Workspaces[0], "groceries.md", time.Hour)
+	addNote(meetings, "weekly.md", 30*24*time.Hour)
+	addNote(meetings, "monthly.md", time.Hour)
+	addNote(mmf.Workspaces[0], "weekend_lyrics.md", time.Hour)
+
+	foundNotes := mmf.FindNotes("weekly", now)
+	if assert.Len(t, foundNotes, 2) {
+		assert.Equal(t, "weekly.md", foundNotes[0].Markdown.Filename)
+		assert.Equal(t, "/meetings", foundNotes[0].NodePath)
+		assert.Equal(t, "weekend_lyrics.md", foundNotes[1].Markdown.Filename)
+	}
+
+	// The node path is matched as well and recently modified notes win over older ones with the same score
+	foundNotes = mmf.FindNotes("mtgly", now)
+	if assert.Len(t, foundNotes, 2) {
+		assert.Equal(t, "monthly.md", foundNotes[0].Markdown.Filename)
+		assert.Equal(t, "weekly.md", foundNotes[1].Markdown.Filename)
+	}
+
+	assert.Empty(t, mmf.FindNotes("xyz", now))
+}
@@ -0,0 +1,185 @@
+package structure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	GraphFormatDot     = "dot"
+	GraphFormatMermaid = "mermaid"
+)
+
+type graphVertex struct {
+	id      string
+	label   string
+	isNote  bool
+	noteKey string
+}
+
+type graphEdge struct {
+	from   string
+	to     string
+	isLink bool
+}
+
+// graph is the hierarchy of a workspace with its nodes and notes as vertices, link edges connect notes.
+type graph struct {
+	name     string
+	vertices []*graphVertex
+	edges    []*graphEdge
+}
+
+func noteKey(node *Node, markdown *Markdown) string {
+	return node.ID + "/" + markdown.ID
+}
+
+func newWorkspaceGraph(workspace *Node) *graph {
+	g := &graph{name: workspace.Name}
+	var walk func(node *Node)
+	walk = func(node *Node) {
+		nodeVertexID := fmt.Sprintf("n%d", len(g.vertices))
+		g.vertices = append(g.vertices, &graphVertex{id: nodeVertexID, label: node.Name})
+		for _, markdown := range node.Markdowns {
+			noteVertexID := fmt.Sprintf("n%d", len(g.vertices))
+			g.vertices = append(g.vertices, &graphVertex{id: noteVertexID, label: markdown.Filename, isNote: true, noteKey: noteKey(node, markdown)})
+			g.edges = append(g.edges, &graphEdge{from: nodeVertexID, to: noteVertexID})
+		}
+		for _, child := range node.Children {
+			g.edges = append(g.edges, &graphEdge{from: nodeVertexID, to: fmt.Sprintf("n%d", len(g.vertices))})
+			walk(child)
+		}
+	}
+	walk(workspace)
+
+	return g
+}
+
+// addLinks adds an edge for every wiki link and relative markdown link between two notes of the workspace,
+// links of a note to itself and links that do not resolve are left out.
+func (g *graph) addLinks(workspace *Node) error {
+	vertexIDs := make(map[string]string)
+	for _, vertex := range g.vertices {
+		if vertex.isNote {
+			vertexIDs[vertex.noteKey] = vertex.id
+		}
+	}
+	notes := workspaceNotes(workspace)
+	notesByPath := make(map[string]*NoteReference)
+	for _, note := range notes {
+		notesByPath[note.FilePath()] = note
+	}
+
+	for _, note := range notes {
+		content, err := os.ReadFile(note.FilePath())
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, body := SplitFrontMatter(content)
+
+		var linkedNotes []*NoteReference
+		for _, link := range ParseWikiLinks(body) {
+			linkedNotes = append(linkedNotes, ResolveWikiLink(notes, note, link.Target))
+		}
+		for _, link := range parseMarkdownLinks(body) {
+			if linkPath, _, _, isLocal := splitLinkTarget(link.target); isLocal && linkPath != "" {
+				linkedNotes = append(linkedNotes, notesByPath[linkFilePath(workspace, note, linkPath)])
+			}
+		}
+
+		from := vertexIDs[noteKey(note.Node, note.Markdown)]
+		isLinked := make(map[string]bool)
+		for _, linkedNote := range linkedNotes {
+			if linkedNote == nil {
+				continue
+			}
+			to := vertexIDs[noteKey(linkedNote.Node, linkedNote.Markdown)]
+			if to == from || isLinked[to] {
+				continue
+			}
+			isLinked[to] = true
+			g.edges = append(g.edges, &graphEdge{from: from, to: to, isLink: true})
+		}
+	}
+
+	return nil
+}
+
+func (g *graph) dot() string {
+	var builder strings.Builder
+	quote := func(text string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+	}
+	fmt.Fprintf(&builder, "digraph %s {\n", quote(g.name))
+	builder.WriteString("  rankdir=LR;\n")
+	for _, vertex := range g.vertices {
+		shape := "folder"
+		if vertex.isNote {
+			shape = "note"
+		}
+		fmt.Fprintf(&builder, "  %s [label=%s, shape=%s];\n", vertex.id, quote(vertex.label), shape)
+	}
+	for _, edge := range g.edges {
+		if edge.isLink {
+			fmt.Fprintf(&builder, "  %s -> %s [style=dashed, color=blue];\n", edge.from, edge.to)
+			continue
+		}
+		fmt.Fprintf(&builder, "  %s -> %s;\n", edge.from, edge.to)
+	}
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+func (g *graph) mermaid() string {
+	var builder strings.Builder
+	quote := func(text string) string {
+		return `"` + strings.ReplaceAll(text, `"`, "#quot;") + `"`
+	}
+	builder.WriteString("graph LR\n")
+	for _, vertex := range g.vertices {
+		if vertex.isNote {
+			fmt.Fprintf(&builder, "  %s(%s)\n", vertex.id, quote(vertex.label))
+			continue
+		}
+		fmt.Fprintf(&builder, "  %s[%s]\n", vertex.id, quote(vertex.label))
+	}
+	for _, edge := range g.edges {
+		if edge.isLink {
+			fmt.Fprintf(&builder, "  %s -.-> %s\n", edge.from, edge.to)
+			continue
+		}
+		fmt.Fprintf(&builder, "  %s --> %s\n", edge.from, edge.to)
+	}
+
+	return builder.String()
+}
+
+// ExportGraph renders the node hierarchy of the active workspace with the notes as leaves in the DOT or Mermaid format,
+// the links between notes are added as dashed edges if requested.
+func (mmf *MetadataNoteWolfyFileHandle) ExportGraph(format string, withLinks bool) (string, error) {
+	if format != GraphFormatDot && format != GraphFormatMermaid {
+		return "", fmt.Errorf("unknown graph format '%s', use either %s or %s", format, GraphFormatDot, GraphFormatMermaid)
+	}
+	activeWorkspace := mmf.FindActiveWorkspace()
+	if activeWorkspace == nil {
+		return "", errors.New("no active workspace, seems like you have not created a workspace yet")
+	}
+
+	g := newWorkspaceGraph(activeWorkspace)
+	if withLinks {
+		if err := g.addLinks(activeWorkspace); err != nil {
+			return "", err
+		}
+	}
+	if format == GraphFormatMermaid {
+		return g.mermaid(), nil
+	}
+
+	return g.dot(), nil
+}
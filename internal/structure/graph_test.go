@@ -0,0 +1,66 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportGraph(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid.New().String())
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	workspacePath := t.TempDir()
+	err = mmf.AddNewWorkspace("research", workspacePath)
+	assert.NoError(t, err)
+	meetings := structure.NewNode("meetings", filepath.Join(workspacePath, "meetings"))
+	err = mmf.AddChild(meetings)
+	assert.NoError(t, err)
+	err = os.MkdirAll(meetings.Path, 0755)
+	assert.NoError(t, err)
+
+	addNote := func(node *structure.Node, filename string, content string) {
+		mmf.AddMarkdownToNode(node, structure.NewMarkdown(filename))
+		err := os.WriteFile(filepath.Join(node.Path, filename), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+	addNote(meetings, "weekly.md", "[[groceries]] [again](../groceries.md) [[weekly]] [[missing]] [web](https://example.com)\n")
+	addNote(mmf.Workspaces[0], `say "hi".md`, "[weekly](meetings/weekly.md#agenda)\n")
+	addNote(mmf.Workspaces[0], "groceries.md", "")
+
+	_, err = mmf.ExportGraph("svg", false)
+	assert.EqualError(t, err, "unknown graph format 'svg', use either dot or mermaid")
+
+	graph, err := mmf.ExportGraph(structure.GraphFormatDot, false)
+	assert.NoError(t, err)
+	expGraph := "digraph \"research\" {\n  rankdir=LR;\n" +
+		"  n0 [label=\"research\", shape=folder];\n" +
+		"  n1 [label=\"say \\\"hi\\\".md\", shape=note];\n" +
+		"  n2 [label=\"groceries.md\", shape=note];\n" +
+		"  n3 [label=\"meetings\", shape=folder];\n" +
+		"  n4 [label=\"weekly.md\", shape=note];\n" +
+		"  n0 -> n1;\n  n0 -> n2;\n  n0 -> n3;\n  n3 -> n4;\n}\n"
+	assert.Equal(t, expGraph, graph)
+
+	graph, err = mmf.ExportGraph(structure.GraphFormatMermaid, true)
+	assert.NoError(t, err)
+	expGraph = "graph LR\n" +
+		"  n0[\"research\"]\n  n1(\"say #quot;hi#quot;.md\")\n  n2(\"groceries.md\")\n  n3[\"meetings\"]\n  n4(\"weekly.md\")\n" +
+		"  n0 --> n1\n  n0 --> n2\n  n0 --> n3\n  n3 --> n4\n" +
+		"  n1 -.-> n4\n  n4 -.-> n2\n"
+	assert.Equal(t, expGraph, graph)
+}
@@ -0,0 +1,91 @@
+package structure
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const IgnoreFileName = ".notewolfyignore"
+
+type ignorePattern struct {
+	pattern       string
+	anchored      bool
+	directoryOnly bool
+	negated       bool
+}
+
+// IgnoreMatcher follows a subset of the gitignore syntax: comments, negation with '!',
+// directory-only patterns with a trailing '/' and patterns anchored to the root with a leading '/'.
+type IgnoreMatcher struct {
+	patterns []ignorePattern
+}
+
+func NewIgnoreMatcher(lines []string) *IgnoreMatcher {
+	matcher := &IgnoreMatcher{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pattern := ignorePattern{}
+		if strings.HasPrefix(line, "!") {
+			pattern.negated = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			pattern.directoryOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.HasPrefix(line, "/") || strings.Contains(line, "/") {
+			pattern.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		pattern.pattern = line
+		matcher.patterns = append(matcher.patterns, pattern)
+	}
+
+	return matcher
+}
+
+func LoadIgnoreFile(rootPath string) (*IgnoreMatcher, error) {
+	file, err := os.Open(filepath.Join(rootPath, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return NewIgnoreMatcher(nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewIgnoreMatcher(lines), nil
+}
+
+// IsIgnored expects the path relative to the root, using '/' as separator.
+func (im *IgnoreMatcher) IsIgnored(relativePath string, isDir bool) bool {
+	ignored := false
+	for _, pattern := range im.patterns {
+		if pattern.directoryOnly && !isDir {
+			continue
+		}
+		target := filepath.Base(relativePath)
+		if pattern.anchored {
+			target = relativePath
+		}
+		if matched, _ := filepath.Match(pattern.pattern, target); matched {
+			ignored = !pattern.negated
+		}
+	}
+
+	return ignored
+}
@@ -0,0 +1,45 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	t.Parallel()
+
+	matcher := structure.NewIgnoreMatcher([]string{
+		"# comment",
+		"",
+		"*.tmp.md",
+		"build/",
+		"/drafts",
+		"archive/old*",
+		"!keep.tmp.md",
+	})
+
+	tests := map[string]struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		"plain markdown file":            {path: "notes/a.md", isDir: false, want: false},
+		"base name pattern":              {path: "notes/a.tmp.md", isDir: false, want: true},
+		"negated pattern":                {path: "notes/keep.tmp.md", isDir: false, want: false},
+		"directory only pattern":         {path: "src/build", isDir: true, want: true},
+		"directory only pattern on file": {path: "src/build", isDir: false, want: false},
+		"anchored pattern at root":       {path: "drafts", isDir: true, want: true},
+		"anchored pattern below root":    {path: "notes/drafts", isDir: true, want: false},
+		"pattern with separator":         {path: "archive/old_notes", isDir: true, want: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, matcher.IsIgnored(tc.path, tc.isDir))
+		})
+	}
+}
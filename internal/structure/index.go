@@ -0,0 +1,413 @@
+package structure
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	indexFileSuffix    = ".index"
+	indexLogFileSuffix = ".log"
+	indexVersion       = 2
+
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// IndexDocument describes an indexed markdown file, size and modification time tell whether it has to be reindexed.
+// The terms of the document tell which posting lists have to be touched when it is removed again.
+type IndexDocument struct {
+	Workspace string    `json:"workspace"`
+	Path      string    `json:"path"`
+	Modified  time.Time `json:"modified"`
+	Size      int64     `json:"size"`
+	Length    int       `json:"length"`
+	Terms     []string  `json:"terms"`
+}
+
+// Index is an inverted index of the note contents, it maps every term to the positions at which it occurs in a note.
+// Notes are identified by the IDs of their markdown files.
+// The index is stored as a snapshot and a log, a save appends the documents that changed to the log and the snapshot
+// is only rewritten once the log has grown larger than the index itself.
+type Index struct {
+	Version   int                         `json:"version"`
+	Documents map[string]*IndexDocument   `json:"documents"`
+	Postings  map[string]map[string][]int `json:"postings"`
+
+	path        string
+	hasSnapshot bool
+	logCount    int
+	changedIDs  map[string]bool
+}
+
+// indexLogRecord is a line of the log, a record without a document removes the document from the index.
+type indexLogRecord struct {
+	ID       string           `json:"id"`
+	Document *IndexDocument   `json:"document,omitempty"`
+	Postings map[string][]int `json:"postings,omitempty"`
+}
+
+type indexToken struct {
+	term  string
+	start int
+	end   int
+}
+
+// tokenize splits the text into lowercase terms of letters and digits together with their byte offsets.
+func tokenize(text string) []indexToken {
+	var tokens []indexToken
+	start := -1
+	for offset, character := range text + " " {
+		isTermCharacter := unicode.IsLetter(character) || unicode.IsDigit(character)
+		if isTermCharacter && start == -1 {
+			start = offset
+		} else if !isTermCharacter && start != -1 {
+			tokens = append(tokens, indexToken{term: strings.ToLower(text[start:offset]), start: start, end: offset})
+			start = -1
+		}
+	}
+
+	return tokens
+}
+
+func newIndex(path string) *Index {
+	return &Index{
+		Version:    indexVersion,
+		Documents:  make(map[string]*IndexDocument),
+		Postings:   make(map[string]map[string][]int),
+		path:       path,
+		changedIDs: make(map[string]bool),
+	}
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) indexFilePath() string {
+	return mmf.Config.MetadataFilePath + indexFileSuffix
+}
+
+func (idx *Index) logFilePath() string {
+	return idx.path + indexLogFileSuffix
+}
+
+// LoadIndex reads the index next to the metadata and replays its log, a missing index or one of an older version
+// starts out empty.
+func (mmf *MetadataNoteWolfyFileHandle) LoadIndex() (*Index, error) {
+	index := newIndex(mmf.indexFilePath())
+	content, err := os.ReadFile(index.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, index); err != nil {
+		return nil, fmt.Errorf("index %s is corrupted, please run 'reindex': %w", index.path, err)
+	}
+	if index.Version != indexVersion {
+		return newIndex(index.path), nil
+	}
+	index.hasSnapshot = true
+	if err := index.replayLog(); err != nil {
+		return nil, fmt.Errorf("index %s is corrupted, please run 'reindex': %w", index.logFilePath(), err)
+	}
+
+	return index, nil
+}
+
+func (idx *Index) replayLog() error {
+	file, err := os.Open(idx.logFilePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, math.MaxInt32)
+	for scanner.Scan() {
+		var record indexLogRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return err
+		}
+		idx.removeDocument(record.ID)
+		if record.Document != nil {
+			idx.Documents[record.ID] = record.Document
+			for term, positions := range record.Postings {
+				if idx.Postings[term] == nil {
+					idx.Postings[term] = make(map[string][]int)
+				}
+				idx.Postings[term][record.ID] = positions
+			}
+		}
+		idx.logCount++
+	}
+	clear(idx.changedIDs)
+
+	return scanner.Err()
+}
+
+// Save appends the changed documents to the log, the snapshot is rewritten if there is none yet
+// or if the log would contain more records than the index has documents.
+func (idx *Index) Save() error {
+	if !idx.hasSnapshot || idx.logCount+len(idx.changedIDs) > len(idx.Documents) {
+		return idx.saveSnapshot()
+	}
+	if len(idx.changedIDs) == 0 {
+		return nil
+	}
+
+	var data bytes.Buffer
+	for _, id := range slices.Sorted(maps.Keys(idx.changedIDs)) {
+		record := indexLogRecord{ID: id, Document: idx.Documents[id]}
+		if record.Document != nil {
+			record.Postings = make(map[string][]int, len(record.Document.Terms))
+			for _, term := range record.Document.Terms {
+				record.Postings[term] = idx.Postings[term][id]
+			}
+		}
+		line, err := json.Marshal(&record)
+		if err != nil {
+			return err
+		}
+		data.Write(append(line, '\n'))
+	}
+	file, err := os.OpenFile(idx.logFilePath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	idx.logCount += len(idx.changedIDs)
+	clear(idx.changedIDs)
+
+	return file.Close()
+}
+
+func (idx *Index) saveSnapshot() error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomically(idx.path, data, 0600); err != nil {
+		return err
+	}
+	// The snapshot already contains the records of the log, replaying them again would not change anything.
+	if err := os.Remove(idx.logFilePath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	idx.hasSnapshot = true
+	idx.logCount = 0
+	clear(idx.changedIDs)
+
+	return nil
+}
+
+func (idx *Index) removeDocument(id string) {
+	document, isIndexed := idx.Documents[id]
+	if !isIndexed {
+		return
+	}
+	delete(idx.Documents, id)
+	for _, term := range document.Terms {
+		delete(idx.Postings[term], id)
+		if len(idx.Postings[term]) == 0 {
+			delete(idx.Postings, term)
+		}
+	}
+	idx.changedIDs[id] = true
+}
+
+func (idx *Index) addDocument(id string, document *IndexDocument, content string) {
+	idx.removeDocument(id)
+	tokens := tokenize(content)
+	document.Length = len(tokens)
+	document.Terms = nil
+	idx.Documents[id] = document
+	for position, token := range tokens {
+		if idx.Postings[token.term] == nil {
+			idx.Postings[token.term] = make(map[string][]int)
+		}
+		if len(idx.Postings[token.term][id]) == 0 {
+			document.Terms = append(document.Terms, token.term)
+		}
+		idx.Postings[token.term][id] = append(idx.Postings[token.term][id], position)
+	}
+	idx.changedIDs[id] = true
+}
+
+// update indexes the notes that are not indexed yet or changed since they were indexed and reports whether it did.
+func (idx *Index) update(notes []*NoteReference) (bool, error) {
+	isUpdated := false
+	for _, note := range notes {
+		path := note.FilePath()
+		fileInfo, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			if _, isIndexed := idx.Documents[note.Markdown.ID]; isIndexed {
+				idx.removeDocument(note.Markdown.ID)
+				isUpdated = true
+			}
+			continue
+		}
+		if err != nil {
+			return isUpdated, err
+		}
+		document := idx.Documents[note.Markdown.ID]
+		if document != nil && document.Path == path && document.Size == fileInfo.Size() && document.Modified.Equal(fileInfo.ModTime()) {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return isUpdated, err
+		}
+		idx.addDocument(note.Markdown.ID, &IndexDocument{
+			Workspace: note.Workspace,
+			Path:      path,
+			Modified:  fileInfo.ModTime(),
+			Size:      fileInfo.Size(),
+		}, string(content))
+		isUpdated = true
+	}
+
+	return isUpdated, nil
+}
+
+// IndexMarkdown updates the index after the markdown file has been created, edited or renamed.
+func (mmf *MetadataNoteWolfyFileHandle) IndexMarkdown(node *Node, markdown *Markdown) error {
+	var notes []*NoteReference
+	for _, note := range mmf.AllWorkspaceNotes() {
+		if note.Node.ID == node.ID && note.Markdown.ID == markdown.ID {
+			notes = append(notes, note)
+		}
+	}
+	if len(notes) == 0 {
+		return fmt.Errorf("markdown file '%s' could not be found on node '%s'", markdown.Filename, node.Name)
+	}
+
+	return mmf.indexNotes(notes)
+}
+
+// IndexNode updates the index for all notes of the node and its child nodes, e.g. after the node has been renamed.
+func (mmf *MetadataNoteWolfyFileHandle) IndexNode(node *Node) error {
+	var notes []*NoteReference
+	for _, note := range mmf.AllWorkspaceNotes() {
+		if note.Node.ID == node.ID || node.Contains(note.Node.ID) {
+			notes = append(notes, note)
+		}
+	}
+
+	return mmf.indexNotes(notes)
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) indexNotes(notes []*NoteReference) error {
+	index, err := mmf.LoadIndex()
+	if err != nil {
+		return err
+	}
+	isUpdated, err := index.update(notes)
+	if err != nil || !isUpdated {
+		return err
+	}
+
+	return index.Save()
+}
+
+// UnindexMarkdown removes a deleted markdown file from the index.
+func (mmf *MetadataNoteWolfyFileHandle) UnindexMarkdown(id string) error {
+	index, err := mmf.LoadIndex()
+	if err != nil {
+		return err
+	}
+	if _, isIndexed := index.Documents[id]; !isIndexed {
+		return nil
+	}
+	index.removeDocument(id)
+
+	return index.Save()
+}
+
+// Reindex rebuilds the index of all workspaces from scratch and returns the number of indexed notes.
+func (mmf *MetadataNoteWolfyFileHandle) Reindex() (int, error) {
+	index := newIndex(mmf.indexFilePath())
+	if _, err := index.update(mmf.AllWorkspaceNotes()); err != nil {
+		return 0, err
+	}
+	if err := index.Save(); err != nil {
+		return 0, err
+	}
+
+	return len(index.Documents), nil
+}
+
+type rankedDocument struct {
+	id    string
+	score float64
+}
+
+// rank returns the IDs of the documents that match the query ordered by their BM25 score,
+// only the given documents are considered and used for the corpus statistics.
+func (idx *Index) rank(query *Query, ids []string) []*rankedDocument {
+	var totalLength int
+	for _, id := range ids {
+		totalLength += idx.Documents[id].Length
+	}
+	averageLength := math.Max(1, float64(totalLength)/float64(max(1, len(ids))))
+	documentFrequencies := make(map[string]int)
+	isInScope := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		isInScope[id] = true
+	}
+
+	query.expand(idx)
+	positiveTerms := query.positiveTerms()
+	var rankedDocuments []*rankedDocument
+	for _, id := range ids {
+		if !query.matches(idx, id) {
+			continue
+		}
+		score := 0.0
+		for _, term := range positiveTerms {
+			positions := idx.Postings[term][id]
+			if len(positions) == 0 {
+				continue
+			}
+			documentFrequency, isCounted := documentFrequencies[term]
+			if !isCounted {
+				for postingID := range idx.Postings[term] {
+					if isInScope[postingID] {
+						documentFrequency++
+					}
+				}
+				documentFrequencies[term] = documentFrequency
+			}
+			idf := math.Log(1 + (float64(len(ids))-float64(documentFrequency)+0.5)/(float64(documentFrequency)+0.5))
+			termFrequency := float64(len(positions))
+			lengthNorm := 1 - bm25B + bm25B*float64(idx.Documents[id].Length)/averageLength
+			score += idf * termFrequency * (bm25K1 + 1) / (termFrequency + bm25K1*lengthNorm)
+		}
+		rankedDocuments = append(rankedDocuments, &rankedDocument{id: id, score: score})
+	}
+	// Equal scores keep the order of the notes in the workspace tree.
+	slices.SortStableFunc(rankedDocuments, func(a *rankedDocument, b *rankedDocument) int {
+		return cmp.Compare(b.score, a.score)
+	})
+
+	return rankedDocuments
+}
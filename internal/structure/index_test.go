@@ -0,0 +1,134 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		query   string
+		wantErr string
+	}{
+		"terms":                 {query: "budget review"},
+		"phrase prefix and not": {query: "\"quarterly review\" budget* -draft OR NOT plan AND notes"},
+		"empty query":           {query: " # ", wantErr: "the search query does not contain any term"},
+		"unterminated phrase":   {query: "\"quarterly review", wantErr: "the phrase \"quarterly review is missing its closing quote"},
+		"leading or":            {query: "OR budget", wantErr: "OR has to be placed between two terms"},
+		"trailing or":           {query: "budget OR", wantErr: "OR has to be placed between two terms"},
+		"trailing not":          {query: "budget NOT", wantErr: "NOT has to be followed by a term"},
+		"only negated terms":    {query: "budget OR -draft", wantErr: "every part of the query needs a term that is not negated"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := structure.ParseQuery(tc.query)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestSearchWithIndex(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid.New().String())
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	workspacePath := t.TempDir()
+	err = mmf.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	contents := map[string]string{
+		"review":   "The quarterly review of the budget\nbudget budget budget\n",
+		"planning": "Planning the budget for the next quarter\nreview quarterly numbers\n",
+		"draft":    "A draft of the quarterly review\n",
+	}
+	for _, name := range []string{"review", "planning", "draft"} {
+		err = os.WriteFile(filepath.Join(workspacePath, name+".md"), []byte(contents[name]), 0644)
+		assert.NoError(t, err)
+		mmf.AddMarkdownToNode(mmf.Workspaces[0], structure.NewMarkdown(name+".md"))
+	}
+
+	searchNotes := func(query string) []string {
+		hits, err := mmf.Search(query, false)
+		assert.NoError(t, err)
+		var filenames []string
+		for _, hit := range hits {
+			if len(filenames) == 0 || filenames[len(filenames)-1] != hit.Note.Markdown.Filename {
+				filenames = append(filenames, hit.Note.Markdown.Filename)
+			}
+		}
+		return filenames
+	}
+
+	assert.Equal(t, []string{"review.md", "planning.md"}, searchNotes("budget"))
+	assert.Equal(t, []string{"draft.md", "review.md"}, searchNotes("\"quarterly review\""))
+	assert.Equal(t, []string{"planning.md", "draft.md", "review.md"}, searchNotes("quarter*"))
+	assert.Equal(t, []string{"review.md", "planning.md"}, searchNotes("quarterly -draft"))
+	assert.Equal(t, []string{"draft.md", "planning.md"}, searchNotes("draft OR next"))
+	assert.Equal(t, []string{"planning.md"}, searchNotes("budget AND NOT \"quarterly review\""))
+	assert.Empty(t, searchNotes("quarterly AND missing"))
+
+	hits, err := mmf.Search("plan*", false)
+	assert.NoError(t, err)
+	if assert.Len(t, hits, 1) {
+		assert.Equal(t, "Planning", hits[0].Snippet[hits[0].MatchStart:hits[0].MatchEnd])
+	}
+
+	// The index is persisted and notes that changed on disk are reindexed before searching
+	index, err := mmf.LoadIndex()
+	assert.NoError(t, err)
+	assert.Len(t, index.Documents, 3)
+	err = os.WriteFile(filepath.Join(workspacePath, "draft.md"), []byte("A draft of the budget, which is longer now\n"), 0644)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"review.md", "draft.md", "planning.md"}, searchNotes("budget"))
+
+	draft := mmf.FindMarkdown(mmf.Workspaces[0], "draft")
+	err = mmf.UnindexMarkdown(draft.ID)
+	assert.NoError(t, err)
+	index, err = mmf.LoadIndex()
+	assert.NoError(t, err)
+	assert.Len(t, index.Documents, 2)
+
+	count, err := mmf.Reindex()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// After a reindex only the changed notes are appended to the log of the index
+	snapshot, err := os.ReadFile(metadataFilePath + ".index")
+	assert.NoError(t, err)
+	assert.NoFileExists(t, metadataFilePath+".index.log")
+	err = os.WriteFile(filepath.Join(workspacePath, "draft.md"), []byte("A plan\n"), 0644)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"draft.md", "planning.md"}, searchNotes("plan*"))
+	content, err := os.ReadFile(metadataFilePath + ".index")
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, content)
+	content, err = os.ReadFile(metadataFilePath + ".index.log")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(content), "\n"))
+	index, err = mmf.LoadIndex()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "plan"}, index.Documents[draft.ID].Terms)
+	assert.NotContains(t, index.Postings["draft"], draft.ID)
+	assert.Contains(t, index.Postings["plan"], draft.ID)
+}
@@ -0,0 +1,625 @@
+package structure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/RaphSku/notewolfy/internal/utility"
+)
+
+const (
+	journalFileSuffix = ".journal"
+	maxJournalEntries = 100
+)
+
+type JournalTarget string
+
+const (
+	JournalNode     JournalTarget = "node"
+	JournalMarkdown JournalTarget = "markdown"
+	JournalPosition JournalTarget = "position"
+	JournalFile     JournalTarget = "file"
+	JournalMove     JournalTarget = "move"
+	JournalCopy     JournalTarget = "copy"
+	JournalTrash    JournalTarget = "trash"
+)
+
+// JournalState describes a tracked resource at one point in time, a nil state means that the resource did not exist.
+// Nodes, markdown files and trash items are located by the ID of their parent and their index, workspaces and
+// the trash items of deleted workspaces have no parent ID. A moved file or directory is described by its location.
+type JournalState struct {
+	ParentID        string     `json:"parentid,omitempty"`
+	Index           int        `json:"index"`
+	Node            *Node      `json:"node,omitempty"`
+	Markdown        *Markdown  `json:"markdown,omitempty"`
+	TrashItem       *TrashItem `json:"trashitem,omitempty"`
+	Location        string     `json:"location,omitempty"`
+	ActiveWorkspace string     `json:"activeworkspace,omitempty"`
+	ActiveNode      string     `json:"activenode,omitempty"`
+	PreviousNode    string     `json:"previousnode,omitempty"`
+	IsDir           bool       `json:"isdir,omitempty"`
+	Content         []byte     `json:"content,omitempty"`
+}
+
+type JournalChange struct {
+	Target JournalTarget `json:"target"`
+	ID     string        `json:"id,omitempty"`
+	Path   string        `json:"path,omitempty"`
+	Before *JournalState `json:"before"`
+	After  *JournalState `json:"after"`
+}
+
+// isRecorded reports whether the states of the change are recorded when it is tracked instead of being captured.
+func (jc *JournalChange) isRecorded() bool {
+	return jc.Target == JournalMove || jc.Target == JournalCopy
+}
+
+// JournalEntry records the states of everything that a command touches before and after it ran,
+// undo restores the states before and redo the states after the command.
+type JournalEntry struct {
+	Description string           `json:"description"`
+	Changes     []*JournalChange `json:"changes"`
+
+	mmf *MetadataNoteWolfyFileHandle
+}
+
+type Journal struct {
+	Undo []*JournalEntry `json:"undo"`
+	Redo []*JournalEntry `json:"redo"`
+
+	path string
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) journalFilePath() string {
+	return mmf.Config.MetadataFilePath + journalFileSuffix
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) LoadJournal() (*Journal, error) {
+	journal := &Journal{path: mmf.journalFilePath()}
+	content, err := os.ReadFile(journal.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return journal, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, journal); err != nil {
+		return nil, fmt.Errorf("journal %s is corrupted: %w", journal.path, err)
+	}
+
+	return journal, nil
+}
+
+func (j *Journal) Save() error {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(j.path, data, 0600)
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) BeginJournalEntry(description string) *JournalEntry {
+	return &JournalEntry{
+		Description: description,
+		mmf:         mmf,
+	}
+}
+
+func (je *JournalEntry) track(change *JournalChange) {
+	if je == nil {
+		return
+	}
+	for _, trackedChange := range je.Changes {
+		if trackedChange.Target == change.Target && trackedChange.ID == change.ID && trackedChange.Path == change.Path {
+			return
+		}
+	}
+	change.Before = je.mmf.captureState(change)
+	je.Changes = append(je.Changes, change)
+}
+
+// TrackNode has to be called before the node is changed, the node does not need to exist yet.
+func (je *JournalEntry) TrackNode(id string) {
+	je.track(&JournalChange{Target: JournalNode, ID: id})
+}
+
+func (je *JournalEntry) TrackMarkdown(id string) {
+	je.track(&JournalChange{Target: JournalMarkdown, ID: id})
+}
+
+func (je *JournalEntry) TrackPosition() {
+	je.track(&JournalChange{Target: JournalPosition})
+}
+
+// TrackFile keeps the content of the file, so that a deleted file can be restored.
+func (je *JournalEntry) TrackFile(path string) {
+	je.track(&JournalChange{Target: JournalFile, Path: path})
+}
+
+func (je *JournalEntry) TrackTrashItem(id string) {
+	je.track(&JournalChange{Target: JournalTrash, ID: id})
+}
+
+// TrackMove records that a file or directory has been moved, the content itself is not kept in the journal.
+func (je *JournalEntry) TrackMove(sourcePath string, targetPath string) {
+	if je == nil {
+		return
+	}
+	je.Changes = append(je.Changes, &JournalChange{
+		Target: JournalMove,
+		Path:   sourcePath,
+		Before: &JournalState{Location: sourcePath},
+		After:  &JournalState{Location: targetPath},
+	})
+}
+
+// TrackCopy records that the file or directory has been copied to the target path, undo removes the copy
+// and redo copies the source again.
+func (je *JournalEntry) TrackCopy(sourcePath string, targetPath string) {
+	if je == nil {
+		return
+	}
+	je.Changes = append(je.Changes, &JournalChange{
+		Target: JournalCopy,
+		Path:   targetPath,
+		After:  &JournalState{Location: sourcePath},
+	})
+}
+
+// CommitJournalEntry records the states after the command and appends the entry to the journal,
+// entries that did not change anything are dropped.
+func (mmf *MetadataNoteWolfyFileHandle) CommitJournalEntry(entry *JournalEntry) error {
+	hasChanges := false
+	for _, change := range entry.Changes {
+		if change.isRecorded() {
+			hasChanges = true
+			continue
+		}
+		change.After = mmf.captureState(change)
+		if !isSameState(change.Before, change.After) {
+			hasChanges = true
+		}
+	}
+	if !hasChanges {
+		return nil
+	}
+
+	journal, err := mmf.LoadJournal()
+	if err != nil {
+		return err
+	}
+	journal.Undo = append(journal.Undo, entry)
+	if len(journal.Undo) > maxJournalEntries {
+		journal.Undo = journal.Undo[len(journal.Undo)-maxJournalEntries:]
+	}
+	journal.Redo = nil
+
+	return journal.Save()
+}
+
+// Undo reverts the most recent journal entry, the metadata still has to be saved by the caller.
+func (mmf *MetadataNoteWolfyFileHandle) Undo() (*JournalEntry, error) {
+	journal, err := mmf.LoadJournal()
+	if err != nil {
+		return nil, err
+	}
+	if len(journal.Undo) == 0 {
+		return nil, errors.New("there is nothing to undo")
+	}
+	entry := journal.Undo[len(journal.Undo)-1]
+	if err := mmf.applyEntry(entry, true); err != nil {
+		return nil, err
+	}
+	mmf.repairPosition()
+
+	journal.Undo = journal.Undo[:len(journal.Undo)-1]
+	journal.Redo = append(journal.Redo, entry)
+
+	return entry, journal.Save()
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) Redo() (*JournalEntry, error) {
+	journal, err := mmf.LoadJournal()
+	if err != nil {
+		return nil, err
+	}
+	if len(journal.Redo) == 0 {
+		return nil, errors.New("there is nothing to redo")
+	}
+	entry := journal.Redo[len(journal.Redo)-1]
+	if err := mmf.applyEntry(entry, false); err != nil {
+		return nil, err
+	}
+	mmf.repairPosition()
+
+	journal.Redo = journal.Redo[:len(journal.Redo)-1]
+	journal.Undo = append(journal.Undo, entry)
+
+	return entry, journal.Save()
+}
+
+// applyEntry restores the states before the entry in reverse order for undo and the states after it for redo.
+// If a change cannot be applied, the already applied changes are rolled back, so that the entry is either applied
+// completely or not at all.
+func (mmf *MetadataNoteWolfyFileHandle) applyEntry(entry *JournalEntry, isUndo bool) error {
+	changes := slices.Clone(entry.Changes)
+	if isUndo {
+		slices.Reverse(changes)
+	}
+	states := func(change *JournalChange) (*JournalState, **JournalState) {
+		if isUndo {
+			return change.Before, &change.After
+		}
+		return change.After, &change.Before
+	}
+
+	var appliedChanges []*JournalChange
+	for _, change := range changes {
+		targetState, currentState := states(change)
+		if !change.isRecorded() {
+			*currentState = mmf.captureState(change)
+		}
+		err := mmf.applyState(change, targetState)
+		if err == nil || !change.isRecorded() {
+			// A captured state can be applied again, even if the change has only been applied partially.
+			appliedChanges = append(appliedChanges, change)
+		}
+		if err == nil {
+			continue
+		}
+
+		var rollbackErrors []error
+		for index := len(appliedChanges) - 1; index >= 0; index-- {
+			_, currentState := states(appliedChanges[index])
+			if rollbackErr := mmf.applyState(appliedChanges[index], *currentState); rollbackErr != nil {
+				rollbackErrors = append(rollbackErrors, rollbackErr)
+			}
+		}
+		if len(rollbackErrors) != 0 {
+			return fmt.Errorf("%w, rolling back the applied changes failed as well: %w", err, errors.Join(rollbackErrors...))
+		}
+		return err
+	}
+
+	return nil
+}
+
+// forgetJournalPaths drops the journal entries that move files from or into one of the removed paths, since they cannot
+// be reverted anymore. The entries before them are dropped as well, because they can only be reverted after them.
+func (mmf *MetadataNoteWolfyFileHandle) forgetJournalPaths(removedPaths []string) error {
+	if len(removedPaths) == 0 {
+		return nil
+	}
+	journal, err := mmf.LoadJournal()
+	if err != nil {
+		return err
+	}
+	forget := func(entries []*JournalEntry) []*JournalEntry {
+		for index := len(entries) - 1; index >= 0; index-- {
+			if entries[index].movesWithin(removedPaths) {
+				return entries[index+1:]
+			}
+		}
+		return entries
+	}
+	undoCount, redoCount := len(journal.Undo), len(journal.Redo)
+	journal.Undo = forget(journal.Undo)
+	journal.Redo = forget(journal.Redo)
+	if len(journal.Undo) == undoCount && len(journal.Redo) == redoCount {
+		return nil
+	}
+
+	return journal.Save()
+}
+
+func (je *JournalEntry) movesWithin(paths []string) bool {
+	for _, change := range je.Changes {
+		if change.Target != JournalMove {
+			continue
+		}
+		for _, path := range paths {
+			for _, location := range []string{change.Before.Location, change.After.Location} {
+				if location == path || strings.HasPrefix(location, path+string(filepath.Separator)) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func isSameState(a *JournalState, b *JournalState) bool {
+	contentA, errA := json.Marshal(a)
+	contentB, errB := json.Marshal(b)
+
+	return errA == nil && errB == nil && bytes.Equal(contentA, contentB)
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) captureState(change *JournalChange) *JournalState {
+	switch change.Target {
+	case JournalNode:
+		return mmf.captureNodeState(change.ID)
+	case JournalMarkdown:
+		return mmf.captureMarkdownState(change.ID)
+	case JournalTrash:
+		return mmf.captureTrashItemState(change.ID)
+	case JournalPosition:
+		return &JournalState{
+			ActiveWorkspace: mmf.ActiveWorkspace,
+			ActiveNode:      mmf.ActiveNode,
+			PreviousNode:    mmf.PreviousNode,
+		}
+	case JournalFile:
+		fileInfo, err := os.Stat(change.Path)
+		if err != nil {
+			return nil
+		}
+		if fileInfo.IsDir() {
+			return &JournalState{IsDir: true}
+		}
+		content, err := os.ReadFile(change.Path)
+		if err != nil {
+			return nil
+		}
+		return &JournalState{Content: content}
+	}
+
+	return nil
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) captureNodeState(id string) *JournalState {
+	for index, workspace := range mmf.Workspaces {
+		if workspace.ID == id {
+			return &JournalState{Index: index, Node: workspace.Clone()}
+		}
+		parentNode := findParentInTree(workspace, id)
+		if parentNode != nil {
+			index := findChildIndex(parentNode, id)
+			return &JournalState{ParentID: parentNode.ID, Index: index, Node: parentNode.Children[index].Clone()}
+		}
+	}
+
+	return nil
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) captureTrashItemState(id string) *JournalState {
+	for index, item := range mmf.Trash {
+		if item.ID == id {
+			return &JournalState{Index: index, TrashItem: item.Clone()}
+		}
+	}
+	for _, workspace := range mmf.Workspaces {
+		for index, item := range workspace.Trash {
+			if item.ID == id {
+				return &JournalState{ParentID: workspace.ID, Index: index, TrashItem: item.Clone()}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) applyTrashItemState(id string, state *JournalState) {
+	mmf.Trash = removeTrashItem(mmf.Trash, id)
+	for _, workspace := range mmf.Workspaces {
+		if trash := removeTrashItem(workspace.Trash, id); len(trash) != len(workspace.Trash) {
+			workspace.Trash = trash
+			mmf.markNodeChanged(workspace)
+		}
+	}
+	if state == nil {
+		return
+	}
+
+	if state.ParentID == "" {
+		mmf.Trash = insertAt(mmf.Trash, state.Index, state.TrashItem.Clone())
+		return
+	}
+	for _, workspace := range mmf.Workspaces {
+		if workspace.ID == state.ParentID {
+			workspace.Trash = insertAt(workspace.Trash, state.Index, state.TrashItem.Clone())
+			mmf.markNodeChanged(workspace)
+		}
+	}
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) captureMarkdownState(id string) *JournalState {
+	var state *JournalState
+	mmf.walkNodes(func(node *Node) bool {
+		for index, markdown := range node.Markdowns {
+			if markdown.ID == id {
+				state = &JournalState{ParentID: node.ID, Index: index, Markdown: markdown.Clone()}
+				return false
+			}
+		}
+		return true
+	})
+
+	return state
+}
+
+// walkNodes visits the nodes of all workspaces until visit returns false.
+func (mmf *MetadataNoteWolfyFileHandle) walkNodes(visit func(node *Node) bool) {
+	queue := NewQueue[*Node]()
+	for _, workspace := range mmf.Workspaces {
+		queue.Add(workspace)
+	}
+	for queue.Len() > 0 {
+		currentNode := queue.Drop()
+		if !visit(currentNode) {
+			return
+		}
+		for _, child := range currentNode.Children {
+			queue.Add(child)
+		}
+	}
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) findNodeInAllWorkspaces(id string) *Node {
+	var foundNode *Node
+	mmf.walkNodes(func(node *Node) bool {
+		if node.ID == id {
+			foundNode = node
+			return false
+		}
+		return true
+	})
+
+	return foundNode
+}
+
+func insertAt[T any](items []T, index int, item T) []T {
+	if index < 0 || index > len(items) {
+		index = len(items)
+	}
+	items = append(items, item)
+	copy(items[index+1:], items[index:])
+	items[index] = item
+
+	return items
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) applyState(change *JournalChange, state *JournalState) error {
+	switch change.Target {
+	case JournalNode:
+		return mmf.applyNodeState(change.ID, state)
+	case JournalMarkdown:
+		return mmf.applyMarkdownState(change.ID, state)
+	case JournalPosition:
+		if state != nil {
+			mmf.ActiveWorkspace = state.ActiveWorkspace
+			mmf.ActiveNode = state.ActiveNode
+			mmf.PreviousNode = state.PreviousNode
+		}
+		return nil
+	case JournalFile:
+		return applyFileState(change.Path, state)
+	case JournalMove:
+		sourcePath := change.After.Location
+		if state == change.After {
+			sourcePath = change.Before.Location
+		}
+		return utility.MovePath(sourcePath, state.Location)
+	case JournalCopy:
+		if state == nil {
+			return os.RemoveAll(change.Path)
+		}
+		return utility.CopyPath(state.Location, change.Path)
+	case JournalTrash:
+		mmf.applyTrashItemState(change.ID, state)
+		return nil
+	}
+
+	return fmt.Errorf("unknown journal target '%s'", change.Target)
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) applyNodeState(id string, state *JournalState) error {
+	for index, workspace := range mmf.Workspaces {
+		if workspace.ID == id {
+			mmf.Workspaces = append(mmf.Workspaces[:index], mmf.Workspaces[index+1:]...)
+			break
+		}
+		if parentNode := findParentInTree(workspace, id); parentNode != nil {
+			mmf.DeleteChild(parentNode, id)
+			break
+		}
+	}
+	if state == nil {
+		return nil
+	}
+
+	restoredNode := state.Node.Clone()
+	mmf.markSubtreeChanged(restoredNode)
+	if state.ParentID == "" {
+		mmf.Workspaces = insertAt(mmf.Workspaces, state.Index, restoredNode)
+		return nil
+	}
+	parentNode := mmf.findNodeInAllWorkspaces(state.ParentID)
+	if parentNode == nil {
+		return fmt.Errorf("node '%s' cannot be restored, since its parent node does not exist anymore", state.Node.Name)
+	}
+	parentNode.Children = insertAt(parentNode.Children, state.Index, restoredNode)
+	mmf.markNodeChanged(parentNode)
+
+	return nil
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) applyMarkdownState(id string, state *JournalState) error {
+	mmf.walkNodes(func(node *Node) bool {
+		for index, markdown := range node.Markdowns {
+			if markdown.ID == id {
+				node.Markdowns = append(node.Markdowns[:index], node.Markdowns[index+1:]...)
+				mmf.markNodeChanged(node)
+				return false
+			}
+		}
+		return true
+	})
+	if state == nil {
+		return nil
+	}
+
+	node := mmf.findNodeInAllWorkspaces(state.ParentID)
+	if node == nil {
+		return fmt.Errorf("markdown file '%s' cannot be restored, since its node does not exist anymore", state.Markdown.Filename)
+	}
+	node.Markdowns = insertAt(node.Markdowns, state.Index, state.Markdown.Clone())
+	mmf.markNodeChanged(node)
+
+	return nil
+}
+
+func applyFileState(path string, state *JournalState) error {
+	if state == nil {
+		fileInfo, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if fileInfo.IsDir() {
+			if err := RemoveWorkspaceMetadata(path); err != nil {
+				return err
+			}
+		}
+		return os.Remove(path)
+	}
+
+	if state.IsDir {
+		return os.MkdirAll(path, 0755)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, state.Content, 0644)
+}
+
+// repairPosition moves the active node back onto an existing node, if undo or redo removed it.
+func (mmf *MetadataNoteWolfyFileHandle) repairPosition() {
+	if mmf.FindActiveWorkspace() == nil {
+		mmf.ActiveWorkspace = ""
+		mmf.ActiveNode = ""
+		mmf.PreviousNode = ""
+		if len(mmf.Workspaces) != 0 {
+			mmf.ActiveWorkspace = mmf.Workspaces[0].Name
+			mmf.ActiveNode = mmf.Workspaces[0].ID
+		}
+		return
+	}
+	if mmf.FindNode(mmf.ActiveNode) == nil {
+		mmf.ActiveNode = mmf.FindActiveWorkspace().ID
+	}
+	if mmf.PreviousNode != "" && mmf.FindNode(mmf.PreviousNode) == nil {
+		mmf.PreviousNode = ""
+	}
+}
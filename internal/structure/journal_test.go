@@ -0,0 +1,144 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalUndoAndRedo(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	workspacePath := t.TempDir()
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	_, err = mmf.Undo()
+	if assert.Error(t, err) {
+		assert.Equal(t, errors.New("there is nothing to undo"), err)
+	}
+
+	err = mmf.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	nodeA := structure.NewNode("A", filepath.Join(workspacePath, "A"))
+	nodeB := structure.NewNode("B", filepath.Join(workspacePath, "B"))
+	err = mmf.AddChild(nodeA)
+	assert.NoError(t, err)
+	err = mmf.AddChild(nodeB)
+	assert.NoError(t, err)
+
+	// Node A is moved below node B and its directory is removed
+	err = os.Mkdir(nodeA.Path, 0755)
+	assert.NoError(t, err)
+	entry := mmf.BeginJournalEntry("move A")
+	entry.TrackNode(nodeA.ID)
+	entry.TrackFile(nodeA.Path)
+	err = mmf.DeleteChild(mmf.Workspaces[0], nodeA.ID)
+	assert.NoError(t, err)
+	nodeB.Children = append(nodeB.Children, nodeA)
+	err = os.Remove(nodeA.Path)
+	assert.NoError(t, err)
+	err = mmf.CommitJournalEntry(entry)
+	assert.NoError(t, err)
+
+	undoneEntry, err := mmf.Undo()
+	assert.NoError(t, err)
+	assert.Equal(t, "move A", undoneEntry.Description)
+	assert.Equal(t, []string{"A", "B"}, []string{mmf.Workspaces[0].Children[0].Name, mmf.Workspaces[0].Children[1].Name})
+	assert.Empty(t, mmf.Workspaces[0].Children[1].Children)
+	_, err = os.Stat(nodeA.Path)
+	assert.NoError(t, err)
+
+	_, err = mmf.Redo()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mmf.Workspaces[0].Children))
+	assert.Equal(t, nodeA.ID, mmf.Workspaces[0].Children[0].Children[0].ID)
+	_, err = os.Stat(nodeA.Path)
+	assert.True(t, os.IsNotExist(err))
+
+	// Entries without any change are not recorded
+	entry = mmf.BeginJournalEntry("nothing")
+	entry.TrackPosition()
+	err = mmf.CommitJournalEntry(entry)
+	assert.NoError(t, err)
+	journal, err := mmf.LoadJournal()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(journal.Undo))
+	assert.Empty(t, journal.Redo)
+}
+
+func TestJournalUndoRollsBackOnFailure(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid.New().String())
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	workspacePath := t.TempDir()
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	err = mmf.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	workspace := mmf.Workspaces[0]
+
+	// The note is moved into the archive directory and a second note is created
+	sourcePath := filepath.Join(workspacePath, "notes.md")
+	targetPath := filepath.Join(workspacePath, "archive", "notes.md")
+	err = os.WriteFile(sourcePath, []byte("# notes"), 0644)
+	assert.NoError(t, err)
+	markdown := structure.NewMarkdown("todo.md")
+	entry := mmf.BeginJournalEntry("archive notes")
+	err = os.MkdirAll(filepath.Dir(targetPath), 0755)
+	assert.NoError(t, err)
+	err = os.Rename(sourcePath, targetPath)
+	assert.NoError(t, err)
+	entry.TrackMove(sourcePath, targetPath)
+	entry.TrackMarkdown(markdown.ID)
+	entry.TrackFile(filepath.Join(workspacePath, "todo.md"))
+	mmf.AddMarkdownToNode(workspace, markdown)
+	err = os.WriteFile(filepath.Join(workspacePath, "todo.md"), []byte("# todo"), 0644)
+	assert.NoError(t, err)
+	err = mmf.CommitJournalEntry(entry)
+	assert.NoError(t, err)
+
+	// The moved note has been removed by hand, so undo fails and keeps the second note
+	err = os.Remove(targetPath)
+	assert.NoError(t, err)
+	_, err = mmf.Undo()
+	assert.Error(t, err)
+	if assert.Len(t, workspace.Markdowns, 1) {
+		assert.Equal(t, markdown.ID, workspace.Markdowns[0].ID)
+	}
+	content, err := os.ReadFile(filepath.Join(workspacePath, "todo.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# todo", string(content))
+	journal, err := mmf.LoadJournal()
+	assert.NoError(t, err)
+	assert.Len(t, journal.Undo, 1)
+	assert.Empty(t, journal.Redo)
+
+	// Once the note is back, undo applies the whole entry
+	err = os.WriteFile(targetPath, []byte("# notes"), 0644)
+	assert.NoError(t, err)
+	_, err = mmf.Undo()
+	assert.NoError(t, err)
+	assert.Empty(t, workspace.Markdowns)
+	assert.FileExists(t, sourcePath)
+	assert.NoFileExists(t, filepath.Join(workspacePath, "todo.md"))
+}
@@ -0,0 +1,176 @@
+package structure
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const codeFenceDelimiter = "```"
+
+// A wiki link is written as [[target]], [[target#heading]] or [[target|label]], the target is a name or a node path with a name.
+var wikiLinkRegex = regexp.MustCompile(`\[\[(?P<target>[^\]\[|#]+)(?P<anchor>#[^\]\[|]*)?(?:\|[^\]\[]*)?\]\]`)
+
+// WikiLink is a [[link]] in a note together with the line that it is written on.
+type WikiLink struct {
+	Target string
+	Anchor string
+	Line   int
+}
+
+var inlineCodeRegex = regexp.MustCompile("`[^`]*`")
+
+// proseLines returns the lines of the content that are not part of a fenced code block by their line number,
+// inline code is blanked out, so that links in code are not taken for real ones.
+func proseLines(content []byte) map[int]string {
+	lines := make(map[int]string)
+	isCodeBlock := false
+	for index, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), codeFenceDelimiter) {
+			isCodeBlock = !isCodeBlock
+			continue
+		}
+		if !isCodeBlock {
+			lines[index+1] = inlineCodeRegex.ReplaceAllStringFunc(line, func(code string) string {
+				return strings.Repeat(" ", len(code))
+			})
+		}
+	}
+
+	return lines
+}
+
+func sortedLineNumbers(lines map[int]string) []int {
+	lineNumbers := make([]int, 0, len(lines))
+	for lineNumber := range lines {
+		lineNumbers = append(lineNumbers, lineNumber)
+	}
+	slices.Sort(lineNumbers)
+
+	return lineNumbers
+}
+
+// ParseWikiLinks returns the wiki links of the content, links inside of code are skipped.
+func ParseWikiLinks(content []byte) []*WikiLink {
+	var links []*WikiLink
+	lines := proseLines(content)
+	for _, lineNumber := range sortedLineNumbers(lines) {
+		for _, matches := range wikiLinkRegex.FindAllStringSubmatch(lines[lineNumber], -1) {
+			target := strings.TrimSuffix(strings.TrimSpace(matches[wikiLinkRegex.SubexpIndex("target")]), MarkdownFileExtension)
+			if target == "" {
+				continue
+			}
+			anchor := strings.TrimPrefix(matches[wikiLinkRegex.SubexpIndex("anchor")], "#")
+			links = append(links, &WikiLink{Target: target, Anchor: strings.TrimSpace(anchor), Line: lineNumber})
+		}
+	}
+
+	return links
+}
+
+// parseWikiLinks returns the distinct targets of the wiki links, which are kept in the metadata as the link graph.
+func parseWikiLinks(content []byte) []string {
+	var targets []string
+	for _, link := range ParseWikiLinks(content) {
+		if !slices.Contains(targets, link.Target) {
+			targets = append(targets, link.Target)
+		}
+	}
+
+	return targets
+}
+
+// ResolveWikiLink finds the note that the target of a link in the given note points to.
+// A plain name prefers a note on the same node, otherwise the first note with this name in the workspace is used.
+// A target with a node path is resolved relative to the node of the note and then from the workspace root.
+func ResolveWikiLink(notes []*NoteReference, from *NoteReference, target string) *NoteReference {
+	nodePath, name := SplitNodePath(target)
+	filename := name + MarkdownFileExtension
+	if nodePath == "" {
+		if note := findNoteByPath(notes, from.NodePath, filename); note != nil {
+			return note
+		}
+		for _, note := range notes {
+			if note.Markdown.Filename == filename {
+				return note
+			}
+		}
+		return nil
+	}
+
+	if !strings.HasPrefix(nodePath, NodePathSeparator) {
+		if note := findNoteByPath(notes, joinNodePath(from.NodePath, nodePath), filename); note != nil {
+			return note
+		}
+	}
+
+	return findNoteByPath(notes, joinNodePath(NodePathSeparator, nodePath), filename)
+}
+
+func joinNodePath(basePath string, nodePath string) string {
+	segments := strings.Split(strings.Trim(basePath, NodePathSeparator), NodePathSeparator)
+	for _, segment := range strings.Split(nodePath, NodePathSeparator) {
+		switch segment {
+		case "", ".":
+		case ParentNodeSegment:
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+		default:
+			segments = append(segments, segment)
+		}
+	}
+
+	return NodePathSeparator + strings.Trim(strings.Join(segments, NodePathSeparator), NodePathSeparator)
+}
+
+func findNoteByPath(notes []*NoteReference, nodePath string, filename string) *NoteReference {
+	for _, note := range notes {
+		if note.NodePath == nodePath && note.Markdown.Filename == filename {
+			return note
+		}
+	}
+
+	return nil
+}
+
+// Links returns the targets of the wiki links of the note together with the notes they resolve to, nil if they do not resolve.
+func (mmf *MetadataNoteWolfyFileHandle) Links(node *Node, markdown *Markdown) ([]string, []*NoteReference) {
+	notes := mmf.ActiveWorkspaceNotes()
+	from := findNote(notes, node, markdown)
+	if from == nil {
+		return nil, nil
+	}
+	var resolvedNotes []*NoteReference
+	for _, target := range markdown.Links {
+		resolvedNotes = append(resolvedNotes, ResolveWikiLink(notes, from, target))
+	}
+
+	return markdown.Links, resolvedNotes
+}
+
+// Backlinks returns the notes of the active workspace that link to the note.
+func (mmf *MetadataNoteWolfyFileHandle) Backlinks(markdown *Markdown) []*NoteReference {
+	notes := mmf.ActiveWorkspaceNotes()
+	var backlinks []*NoteReference
+	for _, note := range notes {
+		for _, target := range note.Markdown.Links {
+			if resolvedNote := ResolveWikiLink(notes, note, target); resolvedNote != nil && resolvedNote.Markdown.ID == markdown.ID {
+				backlinks = append(backlinks, note)
+				break
+			}
+		}
+	}
+
+	return backlinks
+}
+
+func findNote(notes []*NoteReference, node *Node, markdown *Markdown) *NoteReference {
+	for _, note := range notes {
+		if note.Node.ID == node.ID && note.Markdown.ID == markdown.ID {
+			return note
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWikiLinks(t *testing.T) {
+	t.Parallel()
+
+	content := "See [[weekly]] and [[meetings/monthly.md#Agenda|the agenda]].\n```\n[[not a link]]\n```\n[[ weekly ]] [[]]\n"
+	links := structure.ParseWikiLinks([]byte(content))
+	assert.Equal(t, []*structure.WikiLink{
+		{Target: "weekly", Line: 1},
+		{Target: "meetings/monthly", Anchor: "Agenda", Line: 1},
+		{Target: "weekly", Line: 5},
+	}, links)
+}
+
+func TestResolveWikiLink(t *testing.T) {
+	t.Parallel()
+
+	newNote := func(nodePath string, filename string) *structure.NoteReference {
+		return &structure.NoteReference{NodePath: nodePath, Markdown: structure.NewMarkdown(filename)}
+	}
+	rootWeekly := newNote("/", "weekly.md")
+	meetingsWeekly := newNote("/meetings", "weekly.md")
+	meetingsMonthly := newNote("/meetings", "monthly.md")
+	archiveMonthly := newNote("/meetings/archive", "monthly.md")
+	notes := []*structure.NoteReference{rootWeekly, meetingsWeekly, meetingsMonthly, archiveMonthly}
+
+	tests := map[string]struct {
+		from   *structure.NoteReference
+		target string
+		want   *structure.NoteReference
+	}{
+		"name on the same node":             {from: meetingsMonthly, target: "weekly", want: meetingsWeekly},
+		"name on another node":              {from: archiveMonthly, target: "weekly", want: rootWeekly},
+		"node path relative to the note":    {from: meetingsWeekly, target: "archive/monthly", want: archiveMonthly},
+		"node path from the workspace root": {from: archiveMonthly, target: "meetings/monthly", want: meetingsMonthly},
+		"absolute node path":                {from: meetingsWeekly, target: "/weekly", want: rootWeekly},
+		"node path with parent segment":     {from: archiveMonthly, target: "../weekly", want: meetingsWeekly},
+		"missing note":                      {from: rootWeekly, target: "daily", want: nil},
+		"missing note with node path":       {from: rootWeekly, target: "archive/weekly", want: nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, structure.ResolveWikiLink(notes, tc.from, tc.target))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package structure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+const (
+	lockFileSuffix    = ".lock"
+	lockRetryInterval = 50 * time.Millisecond
+	lockTimeout       = 3 * time.Second
+)
+
+type FileLock struct {
+	path string
+	file *os.File
+}
+
+func NewFileLock(path string) *FileLock {
+	return &FileLock{path: path}
+}
+
+func (fl *FileLock) Lock() error {
+	if fl.file != nil {
+		return nil
+	}
+
+	file, err := os.OpenFile(fl.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			fl.file = file
+			return nil
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) {
+			file.Close()
+			return err
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return fmt.Errorf("\n\rAnother notewolfy session is currently using %s, please try again once it has finished!", fl.path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+func (fl *FileLock) Unlock() error {
+	if fl.file == nil {
+		return nil
+	}
+	defer func() {
+		fl.file.Close()
+		fl.file = nil
+	}()
+
+	return syscall.Flock(int(fl.file.Fd()), syscall.LOCK_UN)
+}
+
+func writeFileAtomically(path string, data []byte, perm os.FileMode) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), fmt.Sprintf(".%s-*.tmp", filepath.Base(path)))
+	if err != nil {
+		return err
+	}
+	tempFilePath := tempFile.Name()
+	defer os.Remove(tempFilePath)
+
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tempFilePath, perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFilePath, path)
+}
@@ -0,0 +1,103 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveTruncatesShrinkingMetadata(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+
+	err = mmf.AddNewWorkspace("testA", "/tmp/A")
+	assert.NoError(t, err)
+	err = mmf.AddNewWorkspace("testB", "/tmp/B")
+	assert.NoError(t, err)
+	err = mmf.Save()
+	assert.NoError(t, err)
+
+	mmf.Workspaces = mmf.Workspaces[:1]
+	err = mmf.Save()
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(metadataFilePath)
+	assert.NoError(t, err)
+	var actMmf structure.MetadataNoteWolfyFileHandle
+	err = json.Unmarshal(content, &actMmf)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(actMmf.Workspaces))
+}
+
+func TestLockReloadsMetadata(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmfA, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	mmfB, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+
+	err = mmfA.Lock()
+	assert.NoError(t, err)
+	err = mmfA.AddNewWorkspace("testA", "/tmp/A")
+	assert.NoError(t, err)
+	err = mmfA.Save()
+	assert.NoError(t, err)
+	err = mmfA.Unlock()
+	assert.NoError(t, err)
+
+	err = mmfB.Lock()
+	assert.NoError(t, err)
+	defer mmfB.Unlock()
+	assert.Equal(t, 1, len(mmfB.Workspaces))
+	assert.Equal(t, "testA", mmfB.ActiveWorkspace)
+}
+
+func TestLockRefusesConcurrentSession(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmfA, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	mmfB, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+
+	err = mmfA.Lock()
+	assert.NoError(t, err)
+	defer mmfA.Unlock()
+
+	err = mmfB.Lock()
+	if assert.Error(t, err) {
+		expError := fmt.Errorf("\n\rAnother notewolfy session is currently using %s, please try again once it has finished!", metadataFilePath+".lock")
+		assert.Equal(t, expError, err)
+	}
+}
@@ -1,40 +1,146 @@
 package structure
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/RaphSku/notewolfy/internal/utility"
+	"github.com/google/uuid"
 )
 
 type Config struct {
 	MetadataFilePath string
+	Backend          string
+	Editor           string
+	// Editors maps file extensions like .md to the editor that opens them instead of Editor.
+	Editors              map[string]string
+	DefaultWorkspaceRoot string
+	TrashMaxAge          time.Duration
 }
 
 type Markdown struct {
-	Filename string `json:"filename"`
+	ID       string    `json:"id"`
+	Filename string    `json:"filename"`
+	Title    string    `json:"title,omitempty"`
+	Created  time.Time `json:"created,omitzero"`
+	Modified time.Time `json:"modified,omitzero"`
+	Size     int64     `json:"size,omitempty"`
+	Words    int       `json:"words,omitempty"`
+	Tags     []string  `json:"tags,omitempty"`
+	Links    []string  `json:"links,omitempty"`
+}
+
+func NewMarkdown(filename string) *Markdown {
+	return &Markdown{
+		ID:       uuid.New().String(),
+		Filename: filename,
+	}
 }
 
 type Node struct {
+	ID        string      `json:"id"`
 	Name      string      `json:"name"`
 	Path      string      `json:"path"`
 	Markdowns []*Markdown `json:"markdowns"`
 	Children  []*Node     `json:"children"`
+	// Trash and Adopted are only used on workspace roots.
+	Trash []*TrashItem `json:"trash,omitempty"`
+	// Adopted workspaces keep their tree in the registry, so that nothing is written into the adopted directory.
+	Adopted bool `json:"adopted,omitempty"`
+}
+
+func NewNode(name string, path string) *Node {
+	var nodes []*Node
+	var markdowns []*Markdown
+	return &Node{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Path:      path,
+		Markdowns: markdowns,
+		Children:  nodes,
+	}
+}
+
+func (n *Node) Clone() *Node {
+	clonedNode := &Node{
+		ID:      n.ID,
+		Name:    n.Name,
+		Path:    n.Path,
+		Adopted: n.Adopted,
+	}
+	for _, markdown := range n.Markdowns {
+		clonedNode.Markdowns = append(clonedNode.Markdowns, markdown.Clone())
+	}
+	for _, child := range n.Children {
+		clonedNode.Children = append(clonedNode.Children, child.Clone())
+	}
+	for _, item := range n.Trash {
+		clonedNode.Trash = append(clonedNode.Trash, item.Clone())
+	}
+
+	return clonedNode
+}
+
+// SetPath moves the node to the given path and keeps the paths of all descendants consistent.
+func (n *Node) SetPath(path string) {
+	n.Path = path
+	for _, child := range n.Children {
+		child.SetPath(filepath.Join(path, child.Name))
+	}
+}
+
+func (n *Node) CountDescendants() (int, int) {
+	nodeCount := 0
+	markdownCount := len(n.Markdowns)
+	for _, child := range n.Children {
+		childNodeCount, childMarkdownCount := child.CountDescendants()
+		nodeCount += 1 + childNodeCount
+		markdownCount += childMarkdownCount
+	}
+
+	return nodeCount, markdownCount
+}
+
+// Contains reports whether a descendant of the node has the given ID.
+func (n *Node) Contains(id string) bool {
+	for _, child := range n.Children {
+		if child.ID == id || child.Contains(id) {
+			return true
+		}
+	}
+
+	return false
 }
 
 type MetadataNoteWolfyFileHandle struct {
-	Config          *Config `json:"-"`
-	Workspaces      []*Node `json:"workspaces"`
-	ActiveWorkspace string  `json:"activeworkspace"`
-	ActiveNode      string  `json:"activenode"`
+	Config          *Config       `json:"-"`
+	store           MetadataStore `json:"-"`
+	Version         int           `json:"version"`
+	Workspaces      []*Node       `json:"workspaces"`
+	ActiveWorkspace string        `json:"activeworkspace"`
+	ActiveNode      string        `json:"activenode"`
+	PreviousNode    string        `json:"previousnode"`
+	// Trash holds the deleted workspaces.
+	Trash []*TrashItem `json:"trash,omitempty"`
+	// storedNodeIDs holds the nodes of a store that keeps the workspace trees as of the last load or save,
+	// changedNodeIDs holds the stored nodes that changed since then.
+	storedNodeIDs  map[string]bool `json:"-"`
+	changedNodeIDs map[string]bool `json:"-"`
 }
 
 func NewMetadataNoteWolfyFileHandle(config *Config) (*MetadataNoteWolfyFileHandle, error) {
-	notewolfyFileHandle := &MetadataNoteWolfyFileHandle{Config: config}
-	err := notewolfyFileHandle.load()
+	store, err := NewMetadataStore(config)
+	if err != nil {
+		return nil, err
+	}
+	notewolfyFileHandle := &MetadataNoteWolfyFileHandle{
+		Config: config,
+		store:  store,
+	}
+	err = notewolfyFileHandle.load()
 	if err != nil {
 		return nil, err
 	}
@@ -46,21 +152,32 @@ func (mmf *MetadataNoteWolfyFileHandle) AddNewWorkspace(workspaceName string, wo
 	if err != nil {
 		return err
 	}
-	var nodes []*Node
-	var markdowns []*Markdown
-	newWorkspaceNode := &Node{
-		Name:      workspaceName,
-		Path:      expanedWorkspacePath,
-		Markdowns: markdowns,
-		Children:  nodes,
-	}
-	mmf.Workspaces = append(mmf.Workspaces, newWorkspaceNode)
-	mmf.ActiveWorkspace = workspaceName
-	mmf.ActiveNode = workspaceName
+	mmf.AddWorkspace(NewNode(workspaceName, expanedWorkspacePath))
 
 	return nil
 }
 
+func (mmf *MetadataNoteWolfyFileHandle) AddWorkspace(workspace *Node) {
+	mmf.Workspaces = append(mmf.Workspaces, workspace)
+	mmf.ActiveWorkspace = workspace.Name
+	mmf.ActiveNode = workspace.ID
+	mmf.PreviousNode = ""
+}
+
+// AdoptWorkspace fills the given workspace with the nodes and markdown files found below its path and adds it,
+// it returns the directories and markdown files that were skipped because of their names.
+func (mmf *MetadataNoteWolfyFileHandle) AdoptWorkspace(workspace *Node) ([]string, error) {
+	report, err := mmf.DiffWorkspace(workspace)
+	if err != nil {
+		return nil, err
+	}
+	workspace.Adopted = true
+	mmf.AddWorkspace(workspace)
+	mmf.ApplySyncReport(report)
+
+	return report.Skipped, nil
+}
+
 func (mmf *MetadataNoteWolfyFileHandle) DoesWorkspaceExist(name string) bool {
 	doesExist := false
 	for _, node := range mmf.Workspaces {
@@ -72,27 +189,154 @@ func (mmf *MetadataNoteWolfyFileHandle) DoesWorkspaceExist(name string) bool {
 	return doesExist
 }
 
-func (mmf *MetadataNoteWolfyFileHandle) Save() error {
-	file, err := mmf.getMetadataFile()
+// markNodeChanged records that the record of the node changed, e.g. because of a renamed markdown file.
+// Added and removed nodes do not have to be marked, they are found by comparing the tree with the stored nodes.
+func (mmf *MetadataNoteWolfyFileHandle) markNodeChanged(node *Node) {
+	if mmf.changedNodeIDs == nil {
+		mmf.changedNodeIDs = make(map[string]bool)
+	}
+	mmf.changedNodeIDs[node.ID] = true
+}
+
+// markSubtreeChanged marks the node together with its descendants, e.g. because the paths of the subtree changed.
+func (mmf *MetadataNoteWolfyFileHandle) markSubtreeChanged(node *Node) {
+	mmf.markNodeChanged(node)
+	for _, child := range node.Children {
+		mmf.markSubtreeChanged(child)
+	}
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) markAllNodesChanged() {
+	for _, workspace := range mmf.Workspaces {
+		mmf.markSubtreeChanged(workspace)
+	}
+}
+
+// collectNodes returns the nodes of all workspaces by their IDs, new nodes are marked together with their parent.
+func (mmf *MetadataNoteWolfyFileHandle) collectNodes() (map[string]*Node, error) {
+	nodes := make(map[string]*Node)
+	var collect func(parentNode *Node, node *Node) error
+	collect = func(parentNode *Node, node *Node) error {
+		if _, ok := nodes[node.ID]; ok {
+			return fmt.Errorf("metadata contains the node id %s more than once", node.ID)
+		}
+		nodes[node.ID] = node
+		if !mmf.storedNodeIDs[node.ID] {
+			mmf.markNodeChanged(node)
+			if parentNode != nil {
+				mmf.markNodeChanged(parentNode)
+			}
+		}
+		for _, child := range node.Children {
+			if err := collect(node, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, workspace := range mmf.Workspaces {
+		if err := collect(nil, workspace); err != nil {
+			return nil, err
+		}
+	}
+
+	return nodes, nil
+}
+
+// putChangedNodes passes the changed nodes to the store and deletes the stored nodes that are not part
+// of a workspace anymore.
+func (mmf *MetadataNoteWolfyFileHandle) putChangedNodes() (map[string]bool, error) {
+	nodes, err := mmf.collectNodes()
 	if err != nil {
+		return nil, err
+	}
+	for id := range mmf.storedNodeIDs {
+		if nodes[id] == nil {
+			if err := mmf.store.DeleteNode(id); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for id := range mmf.changedNodeIDs {
+		if node := nodes[id]; node != nil {
+			if err := mmf.store.PutNode(node); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	nodeIDs := make(map[string]bool)
+	for id := range nodes {
+		nodeIDs[id] = true
+	}
+
+	return nodeIDs, nil
+}
+
+// Save writes the tree of every workspace into its root and keeps only the location of the workspace
+// in the registry of the store. Workspaces whose root does not exist keep their tree in the registry.
+// A store that keeps the trees itself only gets the nodes that changed since the last load or save.
+func (mmf *MetadataNoteWolfyFileHandle) Save() error {
+	registry := *mmf
+	if mmf.store.KeepsWorkspaceTrees() {
+		nodeIDs, err := mmf.putChangedNodes()
+		if err != nil {
+			return err
+		}
+		err = mmf.store.Save(&registry)
+		mmf.Version = registry.Version
+		if err == nil {
+			mmf.storedNodeIDs = nodeIDs
+			mmf.changedNodeIDs = nil
+		}
 		return err
 	}
-	defer file.Close()
+	registry.Workspaces = nil
+	for _, workspace := range mmf.Workspaces {
+		isSaved, err := saveWorkspaceTree(workspace)
+		if err != nil {
+			return err
+		}
+		if !isSaved {
+			registry.Workspaces = append(registry.Workspaces, workspace)
+			continue
+		}
+		registry.Workspaces = append(registry.Workspaces, &Node{ID: workspace.ID, Name: workspace.Name, Path: workspace.Path})
+	}
+	err := mmf.store.Save(&registry)
+	mmf.Version = registry.Version
+	if err == nil {
+		mmf.changedNodeIDs = nil
+	}
+
+	return err
+}
 
-	encoder := json.NewEncoder(file)
-	if err := encoder.Encode(mmf); err != nil {
+// Lock acquires the advisory lock on the metadata file and reloads the metadata,
+// so that changes from other notewolfy sessions are not overwritten by this one.
+func (mmf *MetadataNoteWolfyFileHandle) Lock() error {
+	if err := mmf.store.Lock(); err != nil {
 		return err
 	}
+	if err := mmf.reload(); err != nil {
+		mmf.store.Unlock()
+		return err
+	}
+
 	return nil
 }
 
+func (mmf *MetadataNoteWolfyFileHandle) Unlock() error {
+	return mmf.store.Unlock()
+}
+
 func (mmf *MetadataNoteWolfyFileHandle) AddChild(childNode *Node) error {
-	activeNodeName := mmf.ActiveNode
-	if activeNodeName == "" {
+	activeNodeID := mmf.ActiveNode
+	if activeNodeID == "" {
 		return errors.New("no active node, seems like you have not created a workspace yet")
 	}
 
-	activeNode := mmf.FindNode(activeNodeName)
+	activeNode := mmf.FindNode(activeNodeID)
 
 	activePath := activeNode.Path
 	childPath := childNode.Path
@@ -106,42 +350,64 @@ func (mmf *MetadataNoteWolfyFileHandle) AddChild(childNode *Node) error {
 }
 
 func (mmf *MetadataNoteWolfyFileHandle) DeleteChildByIndex(index int) error {
-	activeNodeName := mmf.ActiveNode
-	if activeNodeName == "" {
+	activeNodeID := mmf.ActiveNode
+	if activeNodeID == "" {
 		return errors.New("no active node, seems like you have not created a workspace yet")
 	}
 
-	activeNode := mmf.FindNode(activeNodeName)
+	activeNode := mmf.FindNode(activeNodeID)
 	if index < 0 || index >= len(activeNode.Children) {
 		return errors.New("index is out of range, check that the child at this index exists")
 	}
+	mmf.markNodeChanged(activeNode)
 	activeNode.Children = append(activeNode.Children[:index], activeNode.Children[index+1:]...)
 
 	return nil
 }
 
+func (mmf *MetadataNoteWolfyFileHandle) DeleteChild(parentNode *Node, id string) error {
+	for index, child := range parentNode.Children {
+		if child.ID == id {
+			mmf.markNodeChanged(parentNode)
+			parentNode.Children = append(parentNode.Children[:index], parentNode.Children[index+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("node '%s' has no child with the id %s", parentNode.Name, id)
+}
+
 func (mmf *MetadataNoteWolfyFileHandle) AddMarkdown(markdown *Markdown) error {
-	activeNodeName := mmf.ActiveNode
-	if activeNodeName == "" {
+	activeNodeID := mmf.ActiveNode
+	if activeNodeID == "" {
 		return errors.New("no active node, seems like you have not created a workspace yet")
 	}
 
-	activeNode := mmf.FindNode(activeNodeName)
-	activeNode.Markdowns = append(activeNode.Markdowns, markdown)
+	activeNode := mmf.FindNode(activeNodeID)
+	mmf.AddMarkdownToNode(activeNode, markdown)
 
 	return nil
 }
 
+func (mmf *MetadataNoteWolfyFileHandle) AddMarkdownToNode(node *Node, markdown *Markdown) {
+	node.Markdowns = append(node.Markdowns, markdown)
+	mmf.markNodeChanged(node)
+}
+
 func (mmf *MetadataNoteWolfyFileHandle) DeleteMarkdown(markdownName string) error {
-	activeNodeName := mmf.ActiveNode
-	if activeNodeName == "" {
+	activeNodeID := mmf.ActiveNode
+	if activeNodeID == "" {
 		return errors.New("no active node, seems like you have not created a workspace yet")
 	}
 
-	activeNode := mmf.FindNode(activeNodeName)
+	activeNode := mmf.FindNode(activeNodeID)
 
+	return mmf.DeleteMarkdownFromNode(activeNode, markdownName)
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) DeleteMarkdownFromNode(node *Node, markdownName string) error {
 	foundIndex := -1
-	for index, markdown := range activeNode.Markdowns {
+	for index, markdown := range node.Markdowns {
 		if markdown.Filename[:len(markdown.Filename)-3] == markdownName {
 			foundIndex = index
 			break
@@ -152,10 +418,48 @@ func (mmf *MetadataNoteWolfyFileHandle) DeleteMarkdown(markdownName string) erro
 		return fmt.Errorf("markdownName %s matches no name of a markdown note", markdownName)
 	}
 
-	activeNode.Markdowns = append(activeNode.Markdowns[:foundIndex], activeNode.Markdowns[foundIndex+1:]...)
+	node.Markdowns = append(node.Markdowns[:foundIndex], node.Markdowns[foundIndex+1:]...)
+	mmf.markNodeChanged(node)
 	return nil
 }
 
+func (mmf *MetadataNoteWolfyFileHandle) FindMarkdown(node *Node, markdownName string) *Markdown {
+	for _, markdown := range node.Markdowns {
+		if markdown.Filename[:len(markdown.Filename)-3] == markdownName {
+			return markdown
+		}
+	}
+
+	return nil
+}
+
+// RefreshMarkdown reads the metadata of the markdown file of the node from the file on disk.
+func (mmf *MetadataNoteWolfyFileHandle) RefreshMarkdown(node *Node, markdown *Markdown) (bool, error) {
+	isRefreshed, err := markdown.Refresh(filepath.Join(node.Path, markdown.Filename))
+	if isRefreshed {
+		mmf.markNodeChanged(node)
+	}
+
+	return isRefreshed, err
+}
+
+// FindMarkdownByID searches all workspaces for the markdown file and returns it together with its node.
+func (mmf *MetadataNoteWolfyFileHandle) FindMarkdownByID(id string) (*Node, *Markdown) {
+	var foundNode *Node
+	var foundMarkdown *Markdown
+	mmf.walkNodes(func(node *Node) bool {
+		for _, markdown := range node.Markdowns {
+			if markdown.ID == id {
+				foundNode, foundMarkdown = node, markdown
+				return false
+			}
+		}
+		return true
+	})
+
+	return foundNode, foundMarkdown
+}
+
 func (mmf *MetadataNoteWolfyFileHandle) ListWorkspaces() {
 	longestStringLength := 2 * len("Workspace Name")
 	for _, workspace := range mmf.Workspaces {
@@ -189,14 +493,26 @@ func (mmf *MetadataNoteWolfyFileHandle) ListResourcesOnNode(node *Node) {
 	}
 }
 
-func (mmf *MetadataNoteWolfyFileHandle) FindNode(name string) *Node {
-	activeWorkspaceName := mmf.ActiveWorkspace
-	var activeWorkspace *Node
-	for _, workspace := range mmf.Workspaces {
-		if workspace.Name == activeWorkspaceName {
-			activeWorkspace = workspace
+// ListResourcesOnNodeInDetail lists the resources like ListResourcesOnNode, but with the metadata of every markdown file.
+func (mmf *MetadataNoteWolfyFileHandle) ListResourcesOnNodeInDetail(node *Node) {
+	fmt.Println("\r\nYou are on node: ", node.Name)
+	fmt.Println("\rChild nodes:")
+	for _, child := range node.Children {
+		fmt.Println("\r", child.Name)
+	}
+	fmt.Println("\rMarkdown files:")
+	for _, markdown := range node.Markdowns {
+		modified := "-"
+		if !markdown.Modified.IsZero() {
+			modified = markdown.Modified.Local().Format(time.DateTime)
 		}
+		fmt.Printf("\r %s\t%q\t%s\t%s\t%d words\t[%s]\n", markdown.Filename, markdown.Title, modified,
+			utility.FormatBytes(markdown.Size), markdown.Words, strings.Join(markdown.Tags, ", "))
 	}
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) FindNode(id string) *Node {
+	activeWorkspace := mmf.FindActiveWorkspace()
 	if activeWorkspace == nil {
 		return nil
 	}
@@ -207,7 +523,7 @@ func (mmf *MetadataNoteWolfyFileHandle) FindNode(name string) *Node {
 	var node *Node
 	for queue.Len() > 0 {
 		currentNode := queue.Drop()
-		if currentNode.Name == name {
+		if currentNode.ID == id {
 			node = currentNode
 			break
 		}
@@ -219,14 +535,8 @@ func (mmf *MetadataNoteWolfyFileHandle) FindNode(name string) *Node {
 	return node
 }
 
-func (mmf *MetadataNoteWolfyFileHandle) FindParentNode(name string) *Node {
-	activeWorkspaceName := mmf.ActiveWorkspace
-	var activeWorkspace *Node
-	for _, workspace := range mmf.Workspaces {
-		if workspace.Name == activeWorkspaceName {
-			activeWorkspace = workspace
-		}
-	}
+func (mmf *MetadataNoteWolfyFileHandle) FindParentNode(id string) *Node {
+	activeWorkspace := mmf.FindActiveWorkspace()
 	if activeWorkspace == nil {
 		return nil
 	}
@@ -238,7 +548,7 @@ func (mmf *MetadataNoteWolfyFileHandle) FindParentNode(name string) *Node {
 	for queue.Len() > 0 {
 		currentNode := queue.Drop()
 		for _, childNode := range currentNode.Children {
-			if childNode.Name == name {
+			if childNode.ID == id {
 				parentNode = currentNode
 				break
 			}
@@ -250,33 +560,26 @@ func (mmf *MetadataNoteWolfyFileHandle) FindParentNode(name string) *Node {
 }
 
 func (mmf *MetadataNoteWolfyFileHandle) load() error {
-	file, err := mmf.getMetadataFile()
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	fileInfo, err := file.Stat()
-	if err != nil {
+	if err := mmf.store.Load(mmf); err != nil {
 		return err
 	}
-	if fileInfo.Size() == 0 {
+	if mmf.store.KeepsWorkspaceTrees() {
+		mmf.storedNodeIDs = make(map[string]bool)
+		mmf.walkNodes(func(node *Node) bool {
+			mmf.storedNodeIDs[node.ID] = true
+			return true
+		})
 		return nil
 	}
 
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(mmf); err != nil {
-		return err
-	}
-	return nil
+	return mmf.loadWorkspaceTrees()
 }
 
-func (mmf *MetadataNoteWolfyFileHandle) getMetadataFile() (*os.File, error) {
-	metadataFilePath := mmf.Config.MetadataFilePath
-
-	file, err := os.OpenFile(metadataFilePath, os.O_RDWR|os.O_CREATE, 0666)
-	if err != nil {
-		return nil, err
+func (mmf *MetadataNoteWolfyFileHandle) reload() error {
+	*mmf = MetadataNoteWolfyFileHandle{
+		Config: mmf.Config,
+		store:  mmf.store,
 	}
-	return file, nil
+
+	return mmf.load()
 }
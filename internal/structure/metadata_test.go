@@ -24,6 +24,12 @@ func CleanUpFile(filePath string) {
 		fmt.Println("Could not remove metadata file! Please clean it up yourself!")
 		os.Exit(1)
 	}
+	os.Remove(filePath + ".lock")
+	os.Remove(filePath + ".journal")
+	os.RemoveAll(filePath + ".trash")
+	os.Remove(filePath + ".results")
+	os.Remove(filePath + ".index")
+	os.Remove(filePath + ".index.log")
 }
 
 func captureStdOutput(f func()) (string, error) {
@@ -53,8 +59,8 @@ func captureStdOutput(f func()) (string, error) {
 func TestNewMetadataNoteWolfyFileHandle(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -77,8 +83,8 @@ func TestNewMetadataNoteWolfyFileHandle(t *testing.T) {
 func TestCreateNewWorkspace(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -94,6 +100,7 @@ func TestCreateNewWorkspace(t *testing.T) {
 	expWorkspacePath, err := utility.ExpandRelativePaths(expRelativeWorkspacePath)
 	assert.NoError(t, err)
 	expWorkspaceNode := &structure.Node{
+		ID:   mmf.Workspaces[0].ID,
 		Name: "test",
 		Path: expWorkspacePath,
 	}
@@ -105,8 +112,8 @@ func TestCreateNewWorkspace(t *testing.T) {
 func TestDoesWorkspaceExist(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -129,8 +136,8 @@ func TestDoesWorkspaceExist(t *testing.T) {
 func TestSave(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -164,8 +171,8 @@ func TestSave(t *testing.T) {
 func TestAddChildToNodeFailure(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -175,6 +182,7 @@ func TestAddChildToNodeFailure(t *testing.T) {
 	assert.NoError(t, err)
 
 	childNode := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "B",
 		Path: "/B",
 	}
@@ -186,10 +194,11 @@ func TestAddChildToNodeFailure(t *testing.T) {
 	}
 
 	node := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "A",
 		Path: "/A",
 	}
-	mmf.ActiveNode = node.Name
+	mmf.ActiveNode = node.ID
 	mmf.ActiveWorkspace = node.Name
 	mmf.Workspaces = append(mmf.Workspaces, node)
 	err = mmf.AddChild(childNode)
@@ -202,8 +211,8 @@ func TestAddChildToNodeFailure(t *testing.T) {
 func TestAddChildToNodeSuccess(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -215,16 +224,18 @@ func TestAddChildToNodeSuccess(t *testing.T) {
 	var markdowns []*structure.Markdown
 	var children []*structure.Node
 	node := &structure.Node{
+		ID:        uuid.New().String(),
 		Name:      "A",
 		Path:      "~/A",
 		Markdowns: markdowns,
 		Children:  children,
 	}
 	mmf.Workspaces = append(mmf.Workspaces, node)
-	mmf.ActiveNode = node.Name
+	mmf.ActiveNode = node.ID
 	mmf.ActiveWorkspace = node.Name
 
 	childNode := &structure.Node{
+		ID:        uuid.New().String(),
 		Name:      "B",
 		Path:      "~/A/B",
 		Markdowns: markdowns,
@@ -232,7 +243,7 @@ func TestAddChildToNodeSuccess(t *testing.T) {
 	}
 	err = mmf.AddChild(childNode)
 	assert.NoError(t, err)
-	actNode := mmf.FindNode(node.Name)
+	actNode := mmf.FindNode(node.ID)
 	assert.Equal(t, actNode.Children[0], childNode)
 	mmf.Save()
 }
@@ -240,8 +251,8 @@ func TestAddChildToNodeSuccess(t *testing.T) {
 func TestDeleteChildByIndex(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -251,18 +262,22 @@ func TestDeleteChildByIndex(t *testing.T) {
 	assert.NoError(t, err)
 
 	nodeA := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "A",
 		Path: "~/test/A",
 	}
 	nodeB := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "B",
 		Path: "~/test/B",
 	}
 	nodeC := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "C",
 		Path: "~/test/C",
 	}
 	workspaceNode := &structure.Node{
+		ID:       uuid.New().String(),
 		Name:     "Test",
 		Path:     "~/test",
 		Children: []*structure.Node{nodeA, nodeB, nodeC},
@@ -274,7 +289,7 @@ func TestDeleteChildByIndex(t *testing.T) {
 		assert.Equal(t, expErr, err)
 	}
 
-	mmf.ActiveNode = workspaceNode.Name
+	mmf.ActiveNode = workspaceNode.ID
 	mmf.ActiveWorkspace = workspaceNode.Name
 	mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
 
@@ -301,8 +316,8 @@ func TestDeleteChildByIndex(t *testing.T) {
 func TestAddMarkdown(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -314,13 +329,14 @@ func TestAddMarkdown(t *testing.T) {
 	var markdowns []*structure.Markdown
 	var children []*structure.Node
 	node := &structure.Node{
+		ID:        uuid.New().String(),
 		Name:      "test",
 		Path:      "/A",
 		Markdowns: markdowns,
 		Children:  children,
 	}
 	mmf.Workspaces = append(mmf.Workspaces, node)
-	mmf.ActiveNode = node.Name
+	mmf.ActiveNode = node.ID
 	mmf.ActiveWorkspace = node.Name
 
 	markdown := &structure.Markdown{
@@ -328,15 +344,15 @@ func TestAddMarkdown(t *testing.T) {
 	}
 	err = mmf.AddMarkdown(markdown)
 	assert.NoError(t, err)
-	actNode := mmf.FindNode(node.Name)
+	actNode := mmf.FindNode(node.ID)
 	assert.Equal(t, actNode.Markdowns[0].Filename, markdown.Filename)
 }
 
 func TestDeleteMarkdown(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -348,12 +364,13 @@ func TestDeleteMarkdown(t *testing.T) {
 	var markdowns []*structure.Markdown
 	var children []*structure.Node
 	node := &structure.Node{
+		ID:        uuid.New().String(),
 		Name:      "test",
 		Path:      "/A",
 		Markdowns: markdowns,
 		Children:  children,
 	}
-	mmf.ActiveNode = node.Name
+	mmf.ActiveNode = node.ID
 	mmf.ActiveWorkspace = node.Name
 	mmf.Workspaces = append(mmf.Workspaces, node)
 
@@ -366,13 +383,13 @@ func TestDeleteMarkdown(t *testing.T) {
 
 	err = mmf.DeleteMarkdown(markdownName)
 	assert.NoError(t, err)
-	actNode := mmf.FindNode(node.Name)
+	actNode := mmf.FindNode(node.ID)
 	assert.Empty(t, actNode.Markdowns)
 }
 
 func TestListWorkspaces(t *testing.T) {
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -408,8 +425,8 @@ func TestListWorkspaces(t *testing.T) {
 }
 
 func TestListResourcesOnNode(t *testing.T) {
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -426,19 +443,21 @@ func TestListResourcesOnNode(t *testing.T) {
 
 	var children []*structure.Node
 	childNode := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "childTest",
 		Path: "/A/B",
 	}
 	children = append(children, childNode)
 
 	node := &structure.Node{
+		ID:        uuid.New().String(),
 		Name:      "test",
 		Path:      "/A",
 		Markdowns: markdowns,
 		Children:  children,
 	}
 
-	mmf.ActiveNode = node.Name
+	mmf.ActiveNode = node.ID
 	mmf.ActiveWorkspace = node.Name
 	mmf.Workspaces = append(mmf.Workspaces, node)
 
@@ -454,8 +473,8 @@ func TestListResourcesOnNode(t *testing.T) {
 func TestFindNode(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -466,16 +485,19 @@ func TestFindNode(t *testing.T) {
 
 	var childrenOfB []*structure.Node
 	nodeD := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "D",
 		Path: "/A/B/D",
 	}
 	childrenOfB = append(childrenOfB, nodeD)
 	nodeB := &structure.Node{
+		ID:       uuid.New().String(),
 		Name:     "B",
 		Path:     "/A/B",
 		Children: childrenOfB,
 	}
 	nodeC := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "C",
 		Path: "/A/C",
 	}
@@ -483,23 +505,24 @@ func TestFindNode(t *testing.T) {
 	children = append(children, nodeB)
 	children = append(children, nodeC)
 	nodeA := &structure.Node{
+		ID:       uuid.New().String(),
 		Name:     "A",
 		Path:     "/A",
 		Children: children,
 	}
 	mmf.Workspaces = append(mmf.Workspaces, nodeA)
 	mmf.ActiveWorkspace = nodeA.Name
-	mmf.ActiveNode = nodeA.Name
+	mmf.ActiveNode = nodeA.ID
 
-	actNode := mmf.FindNode("D")
+	actNode := mmf.FindNode(nodeD.ID)
 	assert.True(t, reflect.DeepEqual(nodeD, actNode))
 }
 
 func TestFindParentNode(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -510,16 +533,19 @@ func TestFindParentNode(t *testing.T) {
 
 	var childrenOfB []*structure.Node
 	nodeD := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "D",
 		Path: "/A/B/D",
 	}
 	childrenOfB = append(childrenOfB, nodeD)
 	nodeB := &structure.Node{
+		ID:       uuid.New().String(),
 		Name:     "B",
 		Path:     "/A/B",
 		Children: childrenOfB,
 	}
 	nodeC := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "C",
 		Path: "/A/C",
 	}
@@ -527,23 +553,24 @@ func TestFindParentNode(t *testing.T) {
 	children = append(children, nodeB)
 	children = append(children, nodeC)
 	nodeA := &structure.Node{
+		ID:       uuid.New().String(),
 		Name:     "A",
 		Path:     "/A",
 		Children: children,
 	}
 	mmf.Workspaces = append(mmf.Workspaces, nodeA)
 	mmf.ActiveWorkspace = nodeA.Name
-	mmf.ActiveNode = nodeA.Name
+	mmf.ActiveNode = nodeA.ID
 
-	actParentNode := mmf.FindParentNode("D")
+	actParentNode := mmf.FindParentNode(nodeD.ID)
 	assert.True(t, reflect.DeepEqual(nodeB, actParentNode))
 }
 
 func TestDecodingWhileLoading(t *testing.T) {
 	t.Parallel()
 
-	uuid := uuid.New().String()
-	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid)
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
 	config := &structure.Config{
 		MetadataFilePath: metadataFilePath,
 	}
@@ -553,11 +580,12 @@ func TestDecodingWhileLoading(t *testing.T) {
 	assert.NoError(t, err)
 
 	activeNode := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "Active",
 		Path: "/A",
 	}
 	mmf.ActiveWorkspace = activeNode.Name
-	mmf.ActiveNode = activeNode.Name
+	mmf.ActiveNode = activeNode.ID
 	mmf.Workspaces = append(mmf.Workspaces, activeNode)
 
 	err = mmf.Save()
@@ -0,0 +1,232 @@
+package structure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const CurrentSchemaVersion = 4
+
+type Migration struct {
+	From        int
+	Description string
+	Migrate     func(document map[string]any) error
+}
+
+// migrations holds one entry per schema version, each upgrading a metadata document
+// from version From to version From+1. Never change a released migration, append a new one instead.
+var migrations = []Migration{
+	{
+		From:        0,
+		Description: "introduce the schema version field",
+		Migrate:     migrateUnversionedToV1,
+	},
+	{
+		From:        1,
+		Description: "assign stable IDs to nodes and markdown files",
+		Migrate:     migrateV1ToV2,
+	},
+	{
+		From:        2,
+		Description: "move the tree of every workspace into its root",
+		Migrate:     migrateV2ToV3,
+	},
+	{
+		From:        3,
+		Description: "add titles to markdown files",
+		Migrate:     migrateV3ToV4,
+	},
+}
+
+func findMigration(from int) (Migration, error) {
+	for _, migration := range migrations {
+		if migration.From == from {
+			return migration, nil
+		}
+	}
+
+	return Migration{}, fmt.Errorf("no migration registered for metadata schema version %d", from)
+}
+
+func documentVersion(document map[string]any) (int, error) {
+	rawVersion, ok := document["version"]
+	if !ok {
+		return 0, nil
+	}
+	version, ok := rawVersion.(float64)
+	if !ok || version < 0 || version != float64(int(version)) {
+		return 0, fmt.Errorf("metadata schema version %v is not a valid version", rawVersion)
+	}
+
+	return int(version), nil
+}
+
+func MigrateDocument(document map[string]any) error {
+	version, err := documentVersion(document)
+	if err != nil {
+		return err
+	}
+	if version > CurrentSchemaVersion {
+		return fmt.Errorf("metadata schema version %d is newer than the supported version %d, please upgrade notewolfy", version, CurrentSchemaVersion)
+	}
+
+	for version < CurrentSchemaVersion {
+		migration, err := findMigration(version)
+		if err != nil {
+			return err
+		}
+		if err := migration.Migrate(document); err != nil {
+			return fmt.Errorf("migration of metadata from schema version %d failed (%s): %w", version, migration.Description, err)
+		}
+		version++
+		document["version"] = version
+	}
+
+	return nil
+}
+
+func migrateMetadata(metadataFilePath string, content []byte) ([]byte, error) {
+	var document map[string]any
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	if document == nil {
+		return nil, errors.New("metadata file does not contain a JSON object")
+	}
+
+	version, err := documentVersion(document)
+	if err != nil {
+		return nil, err
+	}
+	if version == CurrentSchemaVersion {
+		return content, nil
+	}
+	if version < CurrentSchemaVersion {
+		if err := backupMetadata(metadataFilePath, version, content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := MigrateDocument(document); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(document)
+}
+
+func BackupFilePath(metadataFilePath string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", metadataFilePath, version)
+}
+
+func backupMetadata(metadataFilePath string, version int, content []byte) error {
+	backupFilePath := BackupFilePath(metadataFilePath, version)
+	if _, err := os.Stat(backupFilePath); err == nil {
+		return nil
+	}
+
+	return writeFileAtomically(backupFilePath, content, 0644)
+}
+
+func migrateUnversionedToV1(document map[string]any) error {
+	return nil
+}
+
+func migrateV1ToV2(document map[string]any) error {
+	workspaces, _ := document["workspaces"].([]any)
+	activeWorkspaceName, _ := document["activeworkspace"].(string)
+	activeNodeName, _ := document["activenode"].(string)
+
+	activeNodeID := ""
+	for _, rawWorkspace := range workspaces {
+		workspace, ok := rawWorkspace.(map[string]any)
+		if !ok {
+			return errors.New("workspace entry is not a JSON object")
+		}
+
+		queue := NewQueue[map[string]any]()
+		queue.Add(workspace)
+		for queue.Len() > 0 {
+			node := queue.Drop()
+			if id, _ := node["id"].(string); id == "" {
+				node["id"] = uuid.New().String()
+			}
+			isActiveWorkspace := workspace["name"] == activeWorkspaceName
+			if isActiveWorkspace && activeNodeID == "" && node["name"] == activeNodeName {
+				activeNodeID = node["id"].(string)
+			}
+
+			markdowns, _ := node["markdowns"].([]any)
+			for _, rawMarkdown := range markdowns {
+				markdown, ok := rawMarkdown.(map[string]any)
+				if !ok {
+					return errors.New("markdown entry is not a JSON object")
+				}
+				if id, _ := markdown["id"].(string); id == "" {
+					markdown["id"] = uuid.New().String()
+				}
+			}
+
+			children, _ := node["children"].([]any)
+			for _, rawChild := range children {
+				child, ok := rawChild.(map[string]any)
+				if !ok {
+					return errors.New("node entry is not a JSON object")
+				}
+				queue.Add(child)
+			}
+		}
+	}
+	document["activenode"] = activeNodeID
+
+	return nil
+}
+
+// migrateV2ToV3 leaves the document as it is, the trees are written into the workspace roots on the next save
+// and the registry only keeps the workspace locations from then on.
+func migrateV2ToV3(document map[string]any) error {
+	return nil
+}
+
+// migrateV3ToV4 derives the title of every markdown file from its filename, the remaining
+// statistics of the notes are read from disk on the next sync or edit.
+func migrateV3ToV4(document map[string]any) error {
+	workspaces, _ := document["workspaces"].([]any)
+	queue := NewQueue[map[string]any]()
+	for _, rawWorkspace := range workspaces {
+		workspace, ok := rawWorkspace.(map[string]any)
+		if !ok {
+			return errors.New("workspace entry is not a JSON object")
+		}
+		queue.Add(workspace)
+	}
+	for queue.Len() > 0 {
+		node := queue.Drop()
+		markdowns, _ := node["markdowns"].([]any)
+		for _, rawMarkdown := range markdowns {
+			markdown, ok := rawMarkdown.(map[string]any)
+			if !ok {
+				return errors.New("markdown entry is not a JSON object")
+			}
+			if title, _ := markdown["title"].(string); title == "" {
+				filename, _ := markdown["filename"].(string)
+				markdown["title"] = strings.TrimSuffix(filename, MarkdownFileExtension)
+			}
+		}
+
+		children, _ := node["children"].([]any)
+		for _, rawChild := range children {
+			child, ok := rawChild.(map[string]any)
+			if !ok {
+				return errors.New("node entry is not a JSON object")
+			}
+			queue.Add(child)
+		}
+	}
+
+	return nil
+}
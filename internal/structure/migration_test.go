@@ -0,0 +1,107 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadingUnversionedMetadata(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	legacyContent := `{"workspaces":[{"name":"A","path":"/A","markdowns":[{"filename":"a.md"}],"children":null}],"activeworkspace":"A","activenode":"A"}`
+	err := os.WriteFile(metadataFilePath, []byte(legacyContent), 0644)
+	assert.NoError(t, err)
+	backupFilePath := structure.BackupFilePath(metadataFilePath, 0)
+	defer os.Remove(backupFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	assert.Equal(t, structure.CurrentSchemaVersion, mmf.Version)
+	assert.Equal(t, "A", mmf.ActiveWorkspace)
+	assert.Equal(t, "a.md", mmf.Workspaces[0].Markdowns[0].Filename)
+
+	backupContent, err := os.ReadFile(backupFilePath)
+	assert.NoError(t, err)
+	assert.Equal(t, legacyContent, string(backupContent))
+}
+
+func TestLoadingNewerMetadataFails(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	newerContent := fmt.Sprintf(`{"version":%d,"workspaces":[]}`, structure.CurrentSchemaVersion+1)
+	err := os.WriteFile(metadataFilePath, []byte(newerContent), 0644)
+	assert.NoError(t, err)
+
+	_, err = structure.NewMetadataNoteWolfyFileHandle(config)
+	if assert.Error(t, err) {
+		expError := fmt.Errorf("metadata schema version %d is newer than the supported version %d, please upgrade notewolfy", structure.CurrentSchemaVersion+1, structure.CurrentSchemaVersion)
+		assert.Equal(t, expError, err)
+	}
+}
+
+func TestMigrateDocument(t *testing.T) {
+	t.Parallel()
+
+	document := map[string]any{
+		"workspaces": []any{},
+	}
+	err := structure.MigrateDocument(document)
+	assert.NoError(t, err)
+	assert.Equal(t, structure.CurrentSchemaVersion, document["version"])
+
+	invalidDocument := map[string]any{
+		"version": "one",
+	}
+	err = structure.MigrateDocument(invalidDocument)
+	assert.Error(t, err)
+}
+
+func TestMigratingNodeIDs(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	legacyContent := `{"version":1,"workspaces":[{"name":"W","path":"/W","markdowns":null,"children":[{"name":"A","path":"/W/A","markdowns":null,"children":[{"name":"notes","path":"/W/A/notes","markdowns":[{"filename":"a.md"}],"children":null}]},{"name":"B","path":"/W/B","markdowns":null,"children":[{"name":"notes","path":"/W/B/notes","markdowns":null,"children":null}]}]}],"activeworkspace":"W","activenode":"notes"}`
+	err := os.WriteFile(metadataFilePath, []byte(legacyContent), 0644)
+	assert.NoError(t, err)
+	defer os.Remove(structure.BackupFilePath(metadataFilePath, 1))
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+
+	workspace := mmf.Workspaces[0]
+	notesOfA := workspace.Children[0].Children[0]
+	notesOfB := workspace.Children[1].Children[0]
+	assert.NotEmpty(t, workspace.ID)
+	assert.NotEmpty(t, notesOfA.ID)
+	assert.NotEmpty(t, notesOfA.Markdowns[0].ID)
+	assert.NotEqual(t, notesOfA.ID, notesOfB.ID)
+	assert.Equal(t, notesOfA.ID, mmf.ActiveNode)
+	assert.Equal(t, workspace.Children[1], mmf.FindParentNode(notesOfB.ID))
+}
@@ -0,0 +1,82 @@
+package structure
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+func (mmf *MetadataNoteWolfyFileHandle) findParentInAllWorkspaces(id string) *Node {
+	for _, workspace := range mmf.Workspaces {
+		if parentNode := findParentInTree(workspace, id); parentNode != nil {
+			return parentNode
+		}
+	}
+
+	return nil
+}
+
+// MoveMarkdown moves the markdown file of the source node to the target node, which can be part of another workspace.
+func (mmf *MetadataNoteWolfyFileHandle) MoveMarkdown(entry *JournalEntry, sourceNode *Node, markdown *Markdown, targetNode *Node) error {
+	if sourceNode == targetNode {
+		return fmt.Errorf("markdown file '%s' is already on node '%s'", markdown.Filename, targetNode.Name)
+	}
+	if mmf.FindMarkdown(targetNode, strings.TrimSuffix(markdown.Filename, MarkdownFileExtension)) != nil {
+		return fmt.Errorf("node '%s' already contains a markdown file '%s'", targetNode.Name, markdown.Filename)
+	}
+	targetPath := filepath.Join(targetNode.Path, markdown.Filename)
+	if err := checkTargetPathIsFree(targetPath); err != nil {
+		return err
+	}
+
+	entry.TrackMarkdown(markdown.ID)
+	if err := mmf.moveTrackedPath(entry, filepath.Join(sourceNode.Path, markdown.Filename), targetPath); err != nil {
+		return err
+	}
+	if err := mmf.DeleteMarkdownFromNode(sourceNode, strings.TrimSuffix(markdown.Filename, MarkdownFileExtension)); err != nil {
+		return err
+	}
+	mmf.AddMarkdownToNode(targetNode, markdown)
+
+	return nil
+}
+
+// MoveNode re-parents the node with its subtree below the target node, which can be part of another workspace.
+// If the active node is moved to another workspace, the active node falls back to the root of the active workspace.
+func (mmf *MetadataNoteWolfyFileHandle) MoveNode(entry *JournalEntry, node *Node, targetNode *Node) error {
+	parentNode := mmf.findParentInAllWorkspaces(node.ID)
+	if parentNode == nil {
+		return fmt.Errorf("node '%s' is a workspace root and cannot be moved", node.Name)
+	}
+	if targetNode.ID == node.ID || node.Contains(targetNode.ID) {
+		return fmt.Errorf("node '%s' cannot be moved into itself or one of its child nodes", node.Name)
+	}
+	if parentNode.ID == targetNode.ID {
+		return fmt.Errorf("node '%s' is already a child of node '%s'", node.Name, targetNode.Name)
+	}
+	for _, child := range targetNode.Children {
+		if child.Name == node.Name {
+			return fmt.Errorf("node '%s' already contains a node '%s'", targetNode.Name, node.Name)
+		}
+	}
+	targetPath := filepath.Join(targetNode.Path, node.Name)
+	if err := checkTargetPathIsFree(targetPath); err != nil {
+		return err
+	}
+
+	entry.TrackNode(node.ID)
+	entry.TrackPosition()
+	if err := mmf.moveTrackedPath(entry, node.Path, targetPath); err != nil {
+		return err
+	}
+	if err := mmf.DeleteChild(parentNode, node.ID); err != nil {
+		return err
+	}
+	node.SetPath(targetPath)
+	targetNode.Children = append(targetNode.Children, node)
+	mmf.markNodeChanged(targetNode)
+	mmf.markSubtreeChanged(node)
+	mmf.repairPosition()
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package structure
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---"
+
+// FrontMatter holds the fields of the YAML front matter of a note that notewolfy keeps in its metadata.
+type FrontMatter struct {
+	Title string          `yaml:"title"`
+	Tags  FrontMatterTags `yaml:"tags"`
+}
+
+// FrontMatterTags accepts a single tag as well as a list of tags, e.g. 'tags: work' or 'tags: [work, home]'.
+type FrontMatterTags []string
+
+func (ft *FrontMatterTags) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*ft = FrontMatterTags{value.Value}
+		return nil
+	}
+	var tags []string
+	if err := value.Decode(&tags); err != nil {
+		return err
+	}
+	*ft = tags
+
+	return nil
+}
+
+// SplitFrontMatter returns the YAML front matter between the leading '---' lines and the body of the note,
+// the front matter is empty if the note does not start with one.
+func SplitFrontMatter(content []byte) ([]byte, []byte) {
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	if len(lines) == 0 || strings.TrimSpace(string(lines[0])) != frontMatterDelimiter {
+		return nil, content
+	}
+	for index := 1; index < len(lines); index++ {
+		if strings.TrimSpace(string(lines[index])) == frontMatterDelimiter {
+			return bytes.Join(lines[1:index], nil), bytes.Join(lines[index+1:], nil)
+		}
+	}
+
+	return nil, content
+}
+
+func ParseFrontMatter(content []byte) (*FrontMatter, []byte) {
+	rawFrontMatter, body := SplitFrontMatter(content)
+	frontMatter := &FrontMatter{}
+	if len(rawFrontMatter) != 0 {
+		// Invalid front matter is treated like a note without front matter, the note itself stays readable.
+		if err := yaml.Unmarshal(rawFrontMatter, frontMatter); err != nil {
+			return &FrontMatter{}, body
+		}
+	}
+
+	return frontMatter, body
+}
+
+// noteTitle prefers the title of the front matter over the first heading and the filename.
+func noteTitle(filename string, frontMatter *FrontMatter, body []byte) string {
+	if frontMatter.Title != "" {
+		return frontMatter.Title
+	}
+	for _, line := range strings.Split(string(body), "\n") {
+		if heading, isHeading := strings.CutPrefix(strings.TrimSpace(line), "# "); isHeading && strings.TrimSpace(heading) != "" {
+			return strings.TrimSpace(heading)
+		}
+	}
+
+	return strings.TrimSuffix(filename, MarkdownFileExtension)
+}
+
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+func normalizeTags(tags []string) []string {
+	var normalizedTags []string
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag != "" && !slices.Contains(normalizedTags, tag) {
+			normalizedTags = append(normalizedTags, tag)
+		}
+	}
+	slices.Sort(normalizedTags)
+
+	return normalizedTags
+}
+
+// Refresh reads the note at the given path and updates title, timestamps, size, word count and tags,
+// it reports whether anything changed.
+func (m *Markdown) Refresh(path string) (bool, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	frontMatter, body := ParseFrontMatter(content)
+
+	refreshedMarkdown := m.Clone()
+	refreshedMarkdown.Title = noteTitle(m.Filename, frontMatter, body)
+	refreshedMarkdown.Modified = fileInfo.ModTime().UTC().Truncate(time.Second)
+	if refreshedMarkdown.Created.IsZero() {
+		refreshedMarkdown.Created = refreshedMarkdown.Modified
+	}
+	refreshedMarkdown.Size = fileInfo.Size()
+	refreshedMarkdown.Words = len(strings.Fields(string(body)))
+	refreshedMarkdown.Tags = normalizeTags(frontMatter.Tags)
+	refreshedMarkdown.Links = parseWikiLinks(body)
+
+	isChanged := !m.isEqual(refreshedMarkdown)
+	*m = *refreshedMarkdown
+
+	return isChanged, nil
+}
+
+func (m *Markdown) Clone() *Markdown {
+	clonedMarkdown := *m
+	clonedMarkdown.Tags = slices.Clone(m.Tags)
+	clonedMarkdown.Links = slices.Clone(m.Links)
+
+	return &clonedMarkdown
+}
+
+func (m *Markdown) isEqual(other *Markdown) bool {
+	return m.ID == other.ID && m.Filename == other.Filename && m.Title == other.Title &&
+		m.Created.Equal(other.Created) && m.Modified.Equal(other.Modified) &&
+		m.Size == other.Size && m.Words == other.Words && slices.Equal(m.Tags, other.Tags) && slices.Equal(m.Links, other.Links)
+}
@@ -0,0 +1,95 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content         string
+		wantFrontMatter *structure.FrontMatter
+		wantBody        string
+	}{
+		"note with front matter": {
+			content:         "---\ntitle: Groceries\ntags: [home, Todo]\n---\n# List\n",
+			wantFrontMatter: &structure.FrontMatter{Title: "Groceries", Tags: []string{"home", "Todo"}},
+			wantBody:        "# List\n",
+		},
+		"note with a single tag": {
+			content:         "---\ntitle: Groceries\ntags: home\n---\n# List\n",
+			wantFrontMatter: &structure.FrontMatter{Title: "Groceries", Tags: []string{"home"}},
+			wantBody:        "# List\n",
+		},
+		"note without front matter": {
+			content:         "# List\n---\n",
+			wantFrontMatter: &structure.FrontMatter{},
+			wantBody:        "# List\n---\n",
+		},
+		"note with unterminated front matter": {
+			content:         "---\ntitle: Groceries\n",
+			wantFrontMatter: &structure.FrontMatter{},
+			wantBody:        "---\ntitle: Groceries\n",
+		},
+		"note with invalid front matter": {
+			content:         "---\ntitle: [Groceries\n---\nbody",
+			wantFrontMatter: &structure.FrontMatter{},
+			wantBody:        "body",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			frontMatter, body := structure.ParseFrontMatter([]byte(tc.content))
+			assert.Equal(t, tc.wantFrontMatter, frontMatter)
+			assert.Equal(t, tc.wantBody, string(body))
+		})
+	}
+}
+
+func TestRefreshMarkdown(t *testing.T) {
+	t.Parallel()
+
+	markdownPath := filepath.Join(t.TempDir(), "groceries.md")
+	err := os.WriteFile(markdownPath, []byte(""), 0644)
+	assert.NoError(t, err)
+
+	markdown := structure.NewMarkdown("groceries.md")
+	isRefreshed, err := markdown.Refresh(markdownPath)
+	assert.NoError(t, err)
+	assert.True(t, isRefreshed)
+	assert.Equal(t, "groceries", markdown.Title)
+	assert.False(t, markdown.Created.IsZero())
+	assert.Equal(t, markdown.Created, markdown.Modified)
+	assert.Equal(t, int64(0), markdown.Size)
+	assert.Equal(t, 0, markdown.Words)
+	assert.Empty(t, markdown.Tags)
+	created := markdown.Created
+
+	isRefreshed, err = markdown.Refresh(markdownPath)
+	assert.NoError(t, err)
+	assert.False(t, isRefreshed)
+
+	content := "---\ntags: [Home, '#todo', home]\n---\n# Weekly groceries\nmilk and eggs\n"
+	err = os.WriteFile(markdownPath, []byte(content), 0644)
+	assert.NoError(t, err)
+	isRefreshed, err = markdown.Refresh(markdownPath)
+	assert.NoError(t, err)
+	assert.True(t, isRefreshed)
+	assert.Equal(t, "Weekly groceries", markdown.Title)
+	assert.Equal(t, created, markdown.Created)
+	assert.Equal(t, int64(len(content)), markdown.Size)
+	assert.Equal(t, 6, markdown.Words)
+	assert.Equal(t, []string{"home", "todo"}, markdown.Tags)
+
+	_, err = markdown.Refresh(filepath.Join(t.TempDir(), "missing.md"))
+	assert.Error(t, err)
+}
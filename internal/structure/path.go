@@ -0,0 +1,145 @@
+package structure
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	NodePathSeparator      = "/"
+	WorkspacePathSeparator = ":"
+	ParentNodeSegment      = ".."
+	PreviousNodePath       = "-"
+	// NamePattern is what names of nodes and markdown files have to match, so that node paths can address them.
+	NamePattern = "[\\w]+"
+)
+
+var nameRegex = regexp.MustCompile(fmt.Sprintf("^%s$", NamePattern))
+
+func IsValidName(name string) bool {
+	return nameRegex.MatchString(name)
+}
+
+func SplitNodePath(nodePath string) (string, string) {
+	index := strings.LastIndex(nodePath, NodePathSeparator)
+	if index == -1 {
+		return "", nodePath
+	}
+	if index == 0 {
+		return NodePathSeparator, nodePath[1:]
+	}
+
+	return nodePath[:index], nodePath[index+1:]
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) FindActiveWorkspace() *Node {
+	for _, workspace := range mmf.Workspaces {
+		if workspace.Name == mmf.ActiveWorkspace {
+			return workspace
+		}
+	}
+
+	return nil
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) ResolveNodePath(nodePath string) (*Node, error) {
+	activeWorkspace := mmf.FindActiveWorkspace()
+	if activeWorkspace == nil {
+		return nil, errors.New("no active workspace, seems like you have not created a workspace yet")
+	}
+
+	currentNode := activeWorkspace
+	if !strings.HasPrefix(nodePath, NodePathSeparator) {
+		currentNode = mmf.FindNode(mmf.ActiveNode)
+		if currentNode == nil {
+			return nil, errors.New("no active node, seems like you have not created a workspace yet")
+		}
+	}
+
+	return resolveNodePathFrom(activeWorkspace, currentNode, nodePath)
+}
+
+// ResolveWorkspaceNodePath additionally accepts paths of the form <workspaceName>:<nodePath>,
+// which are resolved from the root of the named workspace.
+func (mmf *MetadataNoteWolfyFileHandle) ResolveWorkspaceNodePath(nodePath string) (*Node, *Node, error) {
+	workspaceName, workspaceNodePath, isWorkspacePath := strings.Cut(nodePath, WorkspacePathSeparator)
+	if !isWorkspacePath {
+		node, err := mmf.ResolveNodePath(nodePath)
+		return mmf.FindActiveWorkspace(), node, err
+	}
+
+	for _, workspace := range mmf.Workspaces {
+		if workspace.Name == workspaceName {
+			node, err := resolveNodePathFrom(workspace, workspace, workspaceNodePath)
+			return workspace, node, err
+		}
+	}
+
+	return nil, nil, fmt.Errorf("could not find workspace '%s' of path '%s'", workspaceName, nodePath)
+}
+
+func resolveNodePathFrom(workspace *Node, currentNode *Node, nodePath string) (*Node, error) {
+	for _, segment := range strings.Split(nodePath, NodePathSeparator) {
+		switch segment {
+		case "", ".":
+			continue
+		case ParentNodeSegment:
+			parentNode := findParentInTree(workspace, currentNode.ID)
+			if parentNode == nil {
+				return nil, fmt.Errorf("path '%s' leads above the workspace root", nodePath)
+			}
+			currentNode = parentNode
+		default:
+			var childNode *Node
+			for _, child := range currentNode.Children {
+				if child.Name == segment {
+					childNode = child
+					break
+				}
+			}
+			if childNode == nil {
+				return nil, fmt.Errorf("could not find node '%s' of path '%s'", segment, nodePath)
+			}
+			currentNode = childNode
+		}
+	}
+
+	return currentNode, nil
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) NodePath(id string) string {
+	var segments []string
+	currentNode := mmf.FindNode(id)
+	for currentNode != nil {
+		parentNode := mmf.FindParentNode(currentNode.ID)
+		if parentNode == nil {
+			break
+		}
+		segments = append([]string{currentNode.Name}, segments...)
+		currentNode = parentNode
+	}
+
+	return NodePathSeparator + strings.Join(segments, NodePathSeparator)
+}
+
+// WalkNodePaths visits the workspace and all of its child nodes together with their node paths.
+func WalkNodePaths(workspace *Node, visit func(nodePath string, node *Node)) {
+	var walk func(nodePath string, node *Node)
+	walk = func(nodePath string, node *Node) {
+		visit(nodePath, node)
+		for _, child := range node.Children {
+			walk(path.Join(nodePath, child.Name), child)
+		}
+	}
+	walk(NodePathSeparator, workspace)
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) SetActiveNode(id string) {
+	if mmf.ActiveNode != id {
+		mmf.PreviousNode = mmf.ActiveNode
+	}
+	mmf.ActiveNode = id
+}
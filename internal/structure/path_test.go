@@ -0,0 +1,115 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitNodePath(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path        string
+		expNodePath string
+		expBaseName string
+	}{
+		"name only":          {path: "note", expNodePath: "", expBaseName: "note"},
+		"relative node path": {path: "a/b/note", expNodePath: "a/b", expBaseName: "note"},
+		"absolute root path": {path: "/note", expNodePath: "/", expBaseName: "note"},
+		"parent node path":   {path: "../note", expNodePath: "..", expBaseName: "note"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actNodePath, actBaseName := structure.SplitNodePath(tc.path)
+			assert.Equal(t, tc.expNodePath, actNodePath)
+			assert.Equal(t, tc.expBaseName, actBaseName)
+		})
+	}
+}
+
+func TestResolveNodePath(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+
+	nodeD := structure.NewNode("D", "/A/B/D")
+	nodeB := structure.NewNode("B", "/A/B")
+	nodeB.Children = append(nodeB.Children, nodeD)
+	nodeC := structure.NewNode("C", "/A/C")
+	nodeA := structure.NewNode("A", "/A")
+	nodeA.Children = append(nodeA.Children, nodeB, nodeC)
+	mmf.Workspaces = append(mmf.Workspaces, nodeA)
+	mmf.ActiveWorkspace = nodeA.Name
+	mmf.ActiveNode = nodeB.ID
+
+	tests := map[string]struct {
+		path    string
+		expNode *structure.Node
+		expErr  error
+	}{
+		"empty path":           {path: "", expNode: nodeB},
+		"child path":           {path: "D", expNode: nodeD},
+		"parent path":          {path: "..", expNode: nodeA},
+		"sibling path":         {path: "../C", expNode: nodeC},
+		"absolute path":        {path: "/B/D", expNode: nodeD},
+		"workspace root":       {path: "/", expNode: nodeA},
+		"path above root":      {path: "../..", expErr: errors.New("path '../..' leads above the workspace root")},
+		"path to missing node": {path: "D/E", expErr: errors.New("could not find node 'E' of path 'D/E'")},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actNode, err := mmf.ResolveNodePath(tc.path)
+			if tc.expErr != nil {
+				assert.Equal(t, tc.expErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expNode, actNode)
+		})
+	}
+
+	assert.Equal(t, "/B/D", mmf.NodePath(nodeD.ID))
+	assert.Equal(t, "/", mmf.NodePath(nodeA.ID))
+}
+
+func TestSetActiveNodeRemembersPreviousNode(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+
+	err = mmf.AddNewWorkspace("A", "/A")
+	assert.NoError(t, err)
+	workspaceID := mmf.ActiveNode
+
+	mmf.SetActiveNode("B")
+	assert.Equal(t, "B", mmf.ActiveNode)
+	assert.Equal(t, workspaceID, mmf.PreviousNode)
+
+	mmf.SetActiveNode("B")
+	assert.Equal(t, workspaceID, mmf.PreviousNode)
+}
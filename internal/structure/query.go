@@ -0,0 +1,255 @@
+package structure
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	queryAndOperator    = "AND"
+	queryOrOperator     = "OR"
+	queryNotOperator    = "NOT"
+	queryNegationPrefix = "-"
+	queryPrefixSuffix   = "*"
+	queryPhraseQuote    = '"'
+)
+
+// queryClause is a term, a prefix or a phrase of terms, only the last term of a phrase can be a prefix.
+type queryClause struct {
+	terms     []string
+	isPrefix  bool
+	isNegated bool
+
+	// candidates holds the indexed terms of every position of the clause, which differ from the terms for prefixes.
+	candidates [][]string
+}
+
+// Query matches a note if one of its groups matches, a group matches if the note contains all of its clauses
+// and none of its negated clauses. This means that AND binds stronger than OR.
+type Query struct {
+	groups [][]*queryClause
+}
+
+// ParseQuery parses queries like `budget "quarterly review" OR plan* NOT draft`, terms are combined with AND by default.
+// Negation is written as NOT or with a leading '-'.
+func ParseQuery(text string) (*Query, error) {
+	words, err := splitQuery(text)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &Query{}
+	var group []*queryClause
+	isNegated := false
+	for _, word := range words {
+		if !word.isPhrase {
+			switch word.text {
+			case queryAndOperator:
+				continue
+			case queryOrOperator:
+				if len(group) == 0 || isNegated {
+					return nil, errors.New("OR has to be placed between two terms")
+				}
+				query.groups = append(query.groups, group)
+				group = nil
+				continue
+			case queryNotOperator:
+				isNegated = true
+				continue
+			}
+		}
+
+		clause := newQueryClause(word)
+		if clause == nil {
+			continue
+		}
+		clause.isNegated = clause.isNegated || isNegated
+		isNegated = false
+		group = append(group, clause)
+	}
+	if isNegated {
+		return nil, errors.New("NOT has to be followed by a term")
+	}
+	if len(group) == 0 {
+		if len(query.groups) != 0 {
+			return nil, errors.New("OR has to be placed between two terms")
+		}
+		return nil, errors.New("the search query does not contain any term")
+	}
+	query.groups = append(query.groups, group)
+
+	for _, group := range query.groups {
+		if !slices.ContainsFunc(group, func(clause *queryClause) bool { return !clause.isNegated }) {
+			return nil, errors.New("every part of the query needs a term that is not negated")
+		}
+	}
+
+	return query, nil
+}
+
+type queryWord struct {
+	text     string
+	isPhrase bool
+}
+
+func splitQuery(text string) ([]*queryWord, error) {
+	var words []*queryWord
+	for {
+		text = strings.TrimLeft(text, " \t")
+		if text == "" {
+			return words, nil
+		}
+
+		negation := ""
+		if strings.HasPrefix(text, queryNegationPrefix+string(queryPhraseQuote)) {
+			negation, text = queryNegationPrefix, text[len(queryNegationPrefix):]
+		}
+		if text[0] == queryPhraseQuote {
+			end := strings.IndexRune(text[1:], queryPhraseQuote)
+			if end == -1 {
+				return nil, fmt.Errorf("the phrase %s is missing its closing quote", text)
+			}
+			phrase := text[1 : end+1]
+			text = text[end+2:]
+			if strings.HasPrefix(text, queryPrefixSuffix) {
+				phrase, text = phrase+queryPrefixSuffix, text[len(queryPrefixSuffix):]
+			}
+			words = append(words, &queryWord{text: negation + phrase, isPhrase: true})
+			continue
+		}
+
+		end := strings.IndexAny(text, " \t")
+		if end == -1 {
+			end = len(text)
+		}
+		words = append(words, &queryWord{text: text[:end]})
+		text = text[end:]
+	}
+}
+
+func newQueryClause(word *queryWord) *queryClause {
+	clause := &queryClause{}
+	text := word.text
+	if strings.HasPrefix(text, queryNegationPrefix) {
+		clause.isNegated = true
+		text = text[len(queryNegationPrefix):]
+	}
+	if strings.HasSuffix(text, queryPrefixSuffix) {
+		clause.isPrefix = true
+		text = strings.TrimSuffix(text, queryPrefixSuffix)
+	}
+	for _, token := range tokenize(text) {
+		clause.terms = append(clause.terms, token.term)
+	}
+	if len(clause.terms) == 0 {
+		return nil
+	}
+
+	return clause
+}
+
+func (q *Query) clauses() []*queryClause {
+	var clauses []*queryClause
+	for _, group := range q.groups {
+		clauses = append(clauses, group...)
+	}
+
+	return clauses
+}
+
+// expand looks up the indexed terms of every clause position, prefixes are expanded to all terms that start with them.
+func (q *Query) expand(idx *Index) {
+	for _, clause := range q.clauses() {
+		clause.candidates = make([][]string, len(clause.terms))
+		for position, term := range clause.terms {
+			if !clause.isPrefix || position != len(clause.terms)-1 {
+				clause.candidates[position] = []string{term}
+				continue
+			}
+			for indexedTerm := range idx.Postings {
+				if strings.HasPrefix(indexedTerm, term) {
+					clause.candidates[position] = append(clause.candidates[position], indexedTerm)
+				}
+			}
+		}
+	}
+}
+
+// positiveTerms returns the expanded terms of all clauses that are not negated, they contribute to the score.
+func (q *Query) positiveTerms() []string {
+	var terms []string
+	for _, clause := range q.clauses() {
+		if clause.isNegated {
+			continue
+		}
+		for _, candidates := range clause.candidates {
+			for _, term := range candidates {
+				if !slices.Contains(terms, term) {
+					terms = append(terms, term)
+				}
+			}
+		}
+	}
+
+	return terms
+}
+
+// IsPositiveTerm reports whether the term is matched by a clause that is not negated, it is used to highlight matches.
+func (q *Query) IsPositiveTerm(term string) bool {
+	for _, clause := range q.clauses() {
+		if clause.isNegated {
+			continue
+		}
+		for position, clauseTerm := range clause.terms {
+			if term == clauseTerm || (clause.isPrefix && position == len(clause.terms)-1 && strings.HasPrefix(term, clauseTerm)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (q *Query) matches(idx *Index, id string) bool {
+	for _, group := range q.groups {
+		isMatch := true
+		for _, clause := range group {
+			if clause.matches(idx, id) == clause.isNegated {
+				isMatch = false
+				break
+			}
+		}
+		if isMatch {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches checks whether the terms of the clause occur one after another in the document.
+func (qc *queryClause) matches(idx *Index, id string) bool {
+	positionsAt := func(position int) map[int]bool {
+		positions := make(map[int]bool)
+		for _, term := range qc.candidates[position] {
+			for _, termPosition := range idx.Postings[term][id] {
+				positions[termPosition-position] = true
+			}
+		}
+		return positions
+	}
+
+	startPositions := positionsAt(0)
+	for position := 1; position < len(qc.terms) && len(startPositions) != 0; position++ {
+		nextStartPositions := positionsAt(position)
+		for startPosition := range startPositions {
+			if !nextStartPositions[startPosition] {
+				delete(startPositions, startPosition)
+			}
+		}
+	}
+
+	return len(startPositions) != 0
+}
@@ -0,0 +1,98 @@
+package structure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func checkTargetPathIsFree(targetPath string) error {
+	if _, err := os.Lstat(targetPath); err == nil {
+		return fmt.Errorf("%s already exists", targetPath)
+	}
+
+	return nil
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) RenameMarkdown(entry *JournalEntry, node *Node, markdown *Markdown, newName string) error {
+	newFilename := newName + MarkdownFileExtension
+	if mmf.FindMarkdown(node, newName) != nil {
+		return fmt.Errorf("node '%s' already contains a markdown file '%s'", node.Name, newFilename)
+	}
+	targetPath := filepath.Join(node.Path, newFilename)
+	if err := checkTargetPathIsFree(targetPath); err != nil {
+		return err
+	}
+
+	entry.TrackMarkdown(markdown.ID)
+	if err := mmf.moveTrackedPath(entry, filepath.Join(node.Path, markdown.Filename), targetPath); err != nil {
+		return err
+	}
+	markdown.Filename = newFilename
+	mmf.markNodeChanged(node)
+
+	return nil
+}
+
+// RenameNode renames the node on disk and updates the paths of its whole subtree.
+func (mmf *MetadataNoteWolfyFileHandle) RenameNode(entry *JournalEntry, node *Node, newName string) error {
+	parentNode := mmf.FindParentNode(node.ID)
+	if parentNode == nil {
+		return fmt.Errorf("node '%s' is a workspace root, rename the workspace instead", node.Name)
+	}
+	for _, child := range parentNode.Children {
+		if child.Name == newName {
+			return fmt.Errorf("node '%s' already contains a node '%s'", parentNode.Name, newName)
+		}
+	}
+	targetPath := filepath.Join(parentNode.Path, newName)
+	if err := checkTargetPathIsFree(targetPath); err != nil {
+		return err
+	}
+
+	entry.TrackNode(node.ID)
+	if err := mmf.moveTrackedPath(entry, node.Path, targetPath); err != nil {
+		return err
+	}
+	node.Name = newName
+	node.SetPath(targetPath)
+	mmf.markSubtreeChanged(node)
+
+	return nil
+}
+
+// RenameWorkspace renames the workspace, its root directory is only renamed as well if it carries the workspace name.
+func (mmf *MetadataNoteWolfyFileHandle) RenameWorkspace(entry *JournalEntry, workspace *Node, newName string) error {
+	if mmf.DoesWorkspaceExist(newName) {
+		return fmt.Errorf("a workspace with the name '%s' already exists", newName)
+	}
+	targetPath := workspace.Path
+	if filepath.Base(workspace.Path) == workspace.Name {
+		targetPath = filepath.Join(filepath.Dir(workspace.Path), newName)
+		if err := checkTargetPathIsFree(targetPath); err != nil {
+			return err
+		}
+	}
+
+	entry.TrackNode(workspace.ID)
+	entry.TrackPosition()
+	if targetPath != workspace.Path {
+		if err := mmf.moveTrackedPath(entry, workspace.Path, targetPath); err != nil {
+			return err
+		}
+	}
+	if mmf.ActiveWorkspace == workspace.Name {
+		mmf.ActiveWorkspace = newName
+	}
+	workspace.Name = newName
+	workspace.SetPath(targetPath)
+	for _, item := range workspace.Trash {
+		if item.Node != nil {
+			item.Node.SetPath(filepath.Join(targetPath, strings.TrimPrefix(filepath.FromSlash(item.OriginalPath), string(filepath.Separator)), item.Name))
+		}
+	}
+	mmf.markSubtreeChanged(workspace)
+
+	return nil
+}
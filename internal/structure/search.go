@@ -0,0 +1,204 @@
+package structure
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	resultsFileSuffix = ".results"
+	maxSnippetLength  = 80
+	snippetEllipsis   = "..."
+)
+
+// SearchHit is a line of a note that contains the query, MatchStart and MatchEnd are byte offsets into the snippet.
+type SearchHit struct {
+	Note       *NoteReference
+	Line       int
+	Snippet    string
+	MatchStart int
+	MatchEnd   int
+}
+
+// Search looks up the query in the index of the notes of the active workspace, or of all workspaces,
+// and returns the matching lines of the notes ordered by the rank of their note.
+// Notes that changed since they were indexed are reindexed first.
+func (mmf *MetadataNoteWolfyFileHandle) Search(text string, allWorkspaces bool) ([]*SearchHit, error) {
+	query, err := ParseQuery(text)
+	if err != nil {
+		return nil, err
+	}
+	notes := mmf.ActiveWorkspaceNotes()
+	if allWorkspaces {
+		notes = mmf.AllWorkspaceNotes()
+	} else if mmf.FindActiveWorkspace() == nil {
+		return nil, errors.New("no active workspace, seems like you have not created a workspace yet")
+	}
+
+	index, err := mmf.LoadIndex()
+	if err != nil {
+		return nil, err
+	}
+	isUpdated, err := index.update(notes)
+	if err != nil {
+		return nil, err
+	}
+	if isUpdated {
+		if err := index.Save(); err != nil {
+			return nil, err
+		}
+	}
+
+	notesByID := make(map[string]*NoteReference, len(notes))
+	var ids []string
+	for _, note := range notes {
+		if _, isIndexed := index.Documents[note.Markdown.ID]; isIndexed {
+			notesByID[note.Markdown.ID] = note
+			ids = append(ids, note.Markdown.ID)
+		}
+	}
+	var hits []*SearchHit
+	for _, rankedDocument := range index.rank(query, ids) {
+		noteHits, err := searchNote(notesByID[rankedDocument.id], query)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, noteHits...)
+	}
+
+	return hits, nil
+}
+
+// searchNote returns the lines of the note that contain a term of the query.
+func searchNote(note *NoteReference, query *Query) ([]*SearchHit, error) {
+	file, err := os.Open(note.FilePath())
+	if errors.Is(err, os.ErrNotExist) {
+		// Notes that were removed outside of notewolfy are skipped until the next sync.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var hits []*SearchHit
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		tokens := tokenize(line)
+		tokenIndex := slices.IndexFunc(tokens, func(token indexToken) bool {
+			return query.IsPositiveTerm(token.term)
+		})
+		if tokenIndex == -1 {
+			continue
+		}
+		hit := newSearchHit(line, tokens[tokenIndex].start, tokens[tokenIndex].end)
+		hit.Note = note
+		hit.Line = lineNumber
+		hits = append(hits, hit)
+	}
+
+	return hits, scanner.Err()
+}
+
+// newSearchHit cuts a snippet of at most maxSnippetLength bytes around the match out of the line.
+func newSearchHit(line string, matchStart int, matchEnd int) *SearchHit {
+	trimmedLine := strings.TrimLeft(line, " \t")
+	offset := len(line) - len(trimmedLine)
+	line = strings.TrimRight(trimmedLine, " \t")
+	matchStart -= offset
+	matchEnd = min(matchEnd-offset, len(line))
+	if len(line) <= maxSnippetLength {
+		return &SearchHit{Snippet: line, MatchStart: matchStart, MatchEnd: matchEnd}
+	}
+
+	snippetStart, snippetEnd := matchStart, matchEnd
+	if matchEnd-matchStart < maxSnippetLength {
+		snippetStart = max(0, matchStart-(maxSnippetLength-(matchEnd-matchStart))/2)
+		snippetEnd = min(len(line), snippetStart+maxSnippetLength)
+		snippetStart = max(0, snippetEnd-maxSnippetLength)
+	}
+	for snippetStart > 0 && !utf8.RuneStart(line[snippetStart]) {
+		snippetStart--
+	}
+	for snippetEnd < len(line) && !utf8.RuneStart(line[snippetEnd]) {
+		snippetEnd++
+	}
+
+	prefix, suffix := "", ""
+	if snippetStart > 0 {
+		prefix = snippetEllipsis
+	}
+	if snippetEnd < len(line) {
+		suffix = snippetEllipsis
+	}
+
+	return &SearchHit{
+		Snippet:    prefix + line[snippetStart:snippetEnd] + suffix,
+		MatchStart: len(prefix) + matchStart - snippetStart,
+		MatchEnd:   len(prefix) + matchEnd - snippetStart,
+	}
+}
+
+type resultReference struct {
+	Workspace  string `json:"workspace"`
+	NodeID     string `json:"nodeid"`
+	MarkdownID string `json:"markdownid"`
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) resultsFilePath() string {
+	return mmf.Config.MetadataFilePath + resultsFileSuffix
+}
+
+// SaveResults remembers the numbered notes of the last search, so that they can be opened with 'edit #<n>'.
+func (mmf *MetadataNoteWolfyFileHandle) SaveResults(notes []*NoteReference) error {
+	references := make([]*resultReference, 0, len(notes))
+	for _, note := range notes {
+		references = append(references, &resultReference{Workspace: note.Workspace, NodeID: note.Node.ID, MarkdownID: note.Markdown.ID})
+	}
+	data, err := json.Marshal(references)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(mmf.resultsFilePath(), data, 0600)
+}
+
+// FindResult returns the note with the given number of the last search, numbers start at 1.
+func (mmf *MetadataNoteWolfyFileHandle) FindResult(number int) (*NoteReference, error) {
+	content, err := os.ReadFile(mmf.resultsFilePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.New("there are no search results yet")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var references []*resultReference
+	if err := json.Unmarshal(content, &references); err != nil {
+		return nil, fmt.Errorf("search results %s are corrupted: %w", mmf.resultsFilePath(), err)
+	}
+	if number < 1 || number > len(references) {
+		return nil, fmt.Errorf("there is no search result #%d", number)
+	}
+
+	reference := references[number-1]
+	for _, workspace := range mmf.Workspaces {
+		if workspace.Name != reference.Workspace {
+			continue
+		}
+		for _, note := range workspaceNotes(workspace) {
+			if note.Node.ID == reference.NodeID && note.Markdown.ID == reference.MarkdownID {
+				return note, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("search result #%d does not exist anymore", number)
+}
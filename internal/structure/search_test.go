@@ -0,0 +1,98 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid.New().String())
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	for _, workspaceName := range []string{"other", "test"} {
+		workspacePath := filepath.Join(t.TempDir(), workspaceName)
+		err = mmf.AddNewWorkspace(workspaceName, workspacePath)
+		assert.NoError(t, err)
+		node := structure.NewNode("meetings", filepath.Join(workspacePath, "meetings"))
+		err = mmf.AddChild(node)
+		assert.NoError(t, err)
+		err = os.MkdirAll(node.Path, 0755)
+		assert.NoError(t, err)
+		mmf.AddMarkdownToNode(node, structure.NewMarkdown("weekly.md"))
+		content := "# Weekly\n\tReview the Budget\n" + strings.Repeat("a", 100) + " budget " + strings.Repeat("b", 100) + "\n"
+		err = os.WriteFile(filepath.Join(node.Path, "weekly.md"), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+	// A note that was deleted outside of notewolfy is skipped
+	mmf.AddMarkdownToNode(mmf.Workspaces[1], structure.NewMarkdown("missing.md"))
+
+	hits, err := mmf.Search("budget", false)
+	assert.NoError(t, err)
+	if assert.Len(t, hits, 2) {
+		assert.Equal(t, "test", hits[0].Note.Workspace)
+		assert.Equal(t, "/meetings", hits[0].Note.NodePath)
+		assert.Equal(t, 2, hits[0].Line)
+		assert.Equal(t, "Review the Budget", hits[0].Snippet)
+		assert.Equal(t, "Budget", hits[0].Snippet[hits[0].MatchStart:hits[0].MatchEnd])
+
+		assert.Equal(t, 3, hits[1].Line)
+		assert.Equal(t, "..."+strings.Repeat("a", 36)+" budget "+strings.Repeat("b", 36)+"...", hits[1].Snippet)
+		assert.Equal(t, "budget", hits[1].Snippet[hits[1].MatchStart:hits[1].MatchEnd])
+	}
+
+	hits, err = mmf.Search("BUDGET", true)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 4)
+	assert.Equal(t, "other", hits[0].Note.Workspace)
+
+	_, err = mmf.Search("  ", false)
+	assert.Error(t, err)
+}
+
+func TestSaveAndFindResults(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid.New().String())
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	err = mmf.AddNewWorkspace("test", t.TempDir())
+	assert.NoError(t, err)
+	markdown := structure.NewMarkdown("weekly.md")
+	mmf.AddMarkdownToNode(mmf.Workspaces[0], markdown)
+
+	_, err = mmf.FindResult(1)
+	assert.EqualError(t, err, "there are no search results yet")
+
+	err = mmf.SaveResults(mmf.ActiveWorkspaceNotes())
+	assert.NoError(t, err)
+	note, err := mmf.FindResult(1)
+	assert.NoError(t, err)
+	assert.Equal(t, markdown, note.Markdown)
+	_, err = mmf.FindResult(2)
+	assert.EqualError(t, err, "there is no search result #2")
+
+	mmf.Workspaces[0].Markdowns = nil
+	_, err = mmf.FindResult(1)
+	assert.EqualError(t, err, "search result #1 does not exist anymore")
+}
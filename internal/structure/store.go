@@ -0,0 +1,76 @@
+package structure
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	JSONBackend = "json"
+	BoltBackend = "bolt"
+)
+
+// MetadataStore persists the metadata of a MetadataNoteWolfyFileHandle. The handle keeps the node tree in memory,
+// a store decides how the tree is read and written and how concurrent notewolfy sessions are serialized.
+// A store that keeps the workspace trees itself gets the nodes that changed through PutNode and DeleteNode,
+// which are written together with the other metadata on save. Otherwise the trees are written into the workspace
+// roots and the store only gets the locations of the workspaces.
+type MetadataStore interface {
+	Load(mmf *MetadataNoteWolfyFileHandle) error
+	Save(mmf *MetadataNoteWolfyFileHandle) error
+	PutNode(node *Node) error
+	DeleteNode(id string) error
+	Lock() error
+	Unlock() error
+	Path() string
+	KeepsWorkspaceTrees() bool
+}
+
+func NewMetadataStore(config *Config) (MetadataStore, error) {
+	switch config.Backend {
+	case "", JSONBackend:
+		return NewJSONFileStore(config.MetadataFilePath), nil
+	case BoltBackend:
+		return NewBoltStore(config.MetadataFilePath), nil
+	}
+
+	return nil, fmt.Errorf("unknown metadata backend '%s', supported backends are '%s' and '%s'", config.Backend, JSONBackend, BoltBackend)
+}
+
+// MigrateStore copies the metadata of the source store into the target store, the target store has to be empty.
+func MigrateStore(source MetadataStore, target MetadataStore) error {
+	if err := source.Lock(); err != nil {
+		return err
+	}
+	defer source.Unlock()
+	if err := target.Lock(); err != nil {
+		return err
+	}
+	defer target.Unlock()
+
+	targetMmf := &MetadataNoteWolfyFileHandle{}
+	if err := target.Load(targetMmf); err != nil {
+		return err
+	}
+	if len(targetMmf.Workspaces) != 0 {
+		return fmt.Errorf("target store %s already contains workspaces", target.Path())
+	}
+
+	sourceMmf := &MetadataNoteWolfyFileHandle{}
+	if err := source.Load(sourceMmf); err != nil {
+		return err
+	}
+	if !source.KeepsWorkspaceTrees() {
+		if err := sourceMmf.loadWorkspaceTrees(); err != nil {
+			return err
+		}
+	}
+	if len(sourceMmf.Workspaces) == 0 {
+		return errors.New("source store does not contain any workspace")
+	}
+
+	// The metadata is saved through the handle, so that a target store that does not keep the workspace trees
+	// gets them written into the workspace roots, where they replace trees that might be outdated.
+	sourceMmf.store = target
+	return sourceMmf.Save()
+}
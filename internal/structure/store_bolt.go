@@ -0,0 +1,353 @@
+package structure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	metaBucket    = []byte("meta")
+	nodesBucket   = []byte("nodes")
+	workspacesKey = "workspaces"
+)
+
+// BoltStore keeps the metadata in an embedded bbolt database. Every node is stored as its own record
+// and the top-level fields of the metadata are stored in a separate bucket, a save only writes the records
+// of the nodes that changed, which keeps saves cheap for workspaces with many notes. For the same reason the
+// workspace trees are kept in the database instead of being rewritten into the workspace roots.
+type BoltStore struct {
+	path string
+	lock *FileLock
+	// changedRecords holds the node records that are written on the next save, a nil record deletes the node.
+	changedRecords map[string][]byte
+}
+
+// boltNodeRecord stores a node without its subtree, the children are referenced by their IDs.
+type boltNodeRecord struct {
+	Node
+	Children []string `json:"children"`
+}
+
+func NewBoltStore(path string) *BoltStore {
+	return &BoltStore{
+		path: path,
+		lock: NewFileLock(path + lockFileSuffix),
+	}
+}
+
+func (bs *BoltStore) Path() string {
+	return bs.path
+}
+
+func (bs *BoltStore) Lock() error {
+	return bs.lock.Lock()
+}
+
+func (bs *BoltStore) Unlock() error {
+	return bs.lock.Unlock()
+}
+
+func (bs *BoltStore) KeepsWorkspaceTrees() bool {
+	return true
+}
+
+func (bs *BoltStore) open() (*bolt.DB, error) {
+	return bolt.Open(bs.path, 0644, &bolt.Options{Timeout: lockTimeout})
+}
+
+func (bs *BoltStore) PutNode(node *Node) error {
+	record, err := encodeNodeRecord(node)
+	if err != nil {
+		return err
+	}
+	if bs.changedRecords == nil {
+		bs.changedRecords = make(map[string][]byte)
+	}
+	bs.changedRecords[node.ID] = record
+
+	return nil
+}
+
+func (bs *BoltStore) DeleteNode(id string) error {
+	if bs.changedRecords == nil {
+		bs.changedRecords = make(map[string][]byte)
+	}
+	bs.changedRecords[id] = nil
+
+	return nil
+}
+
+// Load decodes the node records straight into the tree, only metadata of an older schema version
+// is rebuilt as a document, migrated and rewritten completely on the next save.
+func (bs *BoltStore) Load(mmf *MetadataNoteWolfyFileHandle) error {
+	bs.changedRecords = nil
+	db, err := bs.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var document map[string]any
+	err = db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		nodes := tx.Bucket(nodesBucket)
+		if meta == nil || nodes == nil {
+			return nil
+		}
+
+		document = make(map[string]any)
+		err := meta.ForEach(func(key []byte, value []byte) error {
+			var decodedValue any
+			if err := json.Unmarshal(value, &decodedValue); err != nil {
+				return fmt.Errorf("metadata field '%s' is corrupted: %w", key, err)
+			}
+			document[string(key)] = decodedValue
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		version, err := documentVersion(document)
+		if err != nil {
+			return err
+		}
+
+		workspaceIDs, _ := document[workspacesKey].([]any)
+		if version == CurrentSchemaVersion {
+			delete(document, workspacesKey)
+			content, err := json.Marshal(document)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(content, mmf); err != nil {
+				return err
+			}
+			document = nil
+			for _, workspaceID := range workspaceIDs {
+				workspace, err := decodeNodeRecord(nodes, workspaceID)
+				if err != nil {
+					return err
+				}
+				mmf.Workspaces = append(mmf.Workspaces, workspace)
+			}
+			return nil
+		}
+
+		var workspaces []any
+		for _, workspaceID := range workspaceIDs {
+			workspace, err := buildNodeDocument(nodes, workspaceID)
+			if err != nil {
+				return err
+			}
+			workspaces = append(workspaces, workspace)
+		}
+		document[workspacesKey] = workspaces
+		if version < CurrentSchemaVersion {
+			return bs.backup(tx, version)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	mmf.Version = CurrentSchemaVersion
+	if document == nil {
+		return nil
+	}
+
+	if err := MigrateDocument(document); err != nil {
+		return err
+	}
+	content, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, mmf); err != nil {
+		return err
+	}
+	mmf.markAllNodesChanged()
+
+	return nil
+}
+
+func decodeNodeRecord(nodes *bolt.Bucket, rawID any) (*Node, error) {
+	id, ok := rawID.(string)
+	if !ok {
+		return nil, fmt.Errorf("node id %v is not a string", rawID)
+	}
+	value := nodes.Get([]byte(id))
+	if value == nil {
+		return nil, fmt.Errorf("node record %s is missing", id)
+	}
+	record := &boltNodeRecord{}
+	if err := json.Unmarshal(value, record); err != nil {
+		return nil, fmt.Errorf("node record %s is corrupted: %w", id, err)
+	}
+
+	node := &record.Node
+	for _, childID := range record.Children {
+		child, err := decodeNodeRecord(nodes, childID)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	return node, nil
+}
+
+func buildNodeDocument(nodes *bolt.Bucket, rawID any) (map[string]any, error) {
+	id, ok := rawID.(string)
+	if !ok {
+		return nil, fmt.Errorf("node id %v is not a string", rawID)
+	}
+	record := nodes.Get([]byte(id))
+	if record == nil {
+		return nil, fmt.Errorf("node record %s is missing", id)
+	}
+	var node map[string]any
+	if err := json.Unmarshal(record, &node); err != nil {
+		return nil, fmt.Errorf("node record %s is corrupted: %w", id, err)
+	}
+
+	childIDs, _ := node["children"].([]any)
+	var children []any
+	for _, childID := range childIDs {
+		child, err := buildNodeDocument(nodes, childID)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	node["children"] = children
+
+	return node, nil
+}
+
+func (bs *BoltStore) backup(tx *bolt.Tx, version int) error {
+	backupFilePath := BackupFilePath(bs.path, version)
+	if _, err := os.Stat(backupFilePath); err == nil {
+		return nil
+	}
+
+	return tx.CopyFile(backupFilePath, 0644)
+}
+
+// Save writes the top-level fields of the metadata together with the node records passed to PutNode and DeleteNode.
+func (bs *BoltStore) Save(mmf *MetadataNoteWolfyFileHandle) error {
+	mmf.Version = CurrentSchemaVersion
+	metaRecords, err := encodeMetaRecords(mmf)
+	if err != nil {
+		return err
+	}
+
+	db, err := bs.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		nodes, err := tx.CreateBucketIfNotExists(nodesBucket)
+		if err != nil {
+			return err
+		}
+		if err := putChangedRecords(meta, metaRecords); err != nil {
+			return err
+		}
+
+		for id, record := range bs.changedRecords {
+			if record == nil {
+				err = nodes.Delete([]byte(id))
+			} else {
+				err = nodes.Put([]byte(id), record)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	bs.changedRecords = nil
+
+	return nil
+}
+
+// putChangedRecords only writes records whose content differs from the stored one and deletes records
+// that do not exist anymore.
+func putChangedRecords(bucket *bolt.Bucket, records map[string][]byte) error {
+	var staleKeys [][]byte
+	err := bucket.ForEach(func(key []byte, value []byte) error {
+		if _, ok := records[string(key)]; !ok {
+			staleKeys = append(staleKeys, bytes.Clone(key))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range staleKeys {
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	for key, record := range records {
+		if bytes.Equal(bucket.Get([]byte(key)), record) {
+			continue
+		}
+		if err := bucket.Put([]byte(key), record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func encodeMetaRecords(mmf *MetadataNoteWolfyFileHandle) (map[string][]byte, error) {
+	header := *mmf
+	header.Workspaces = nil
+	content, err := json.Marshal(&header)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return nil, err
+	}
+
+	metaRecords := make(map[string][]byte)
+	for key, value := range fields {
+		metaRecords[key] = value
+	}
+	var workspaceIDs []string
+	for _, workspace := range mmf.Workspaces {
+		workspaceIDs = append(workspaceIDs, workspace.ID)
+	}
+	metaRecords[workspacesKey], err = json.Marshal(workspaceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return metaRecords, nil
+}
+
+func encodeNodeRecord(node *Node) ([]byte, error) {
+	record := &boltNodeRecord{Node: *node, Children: []string{}}
+	for _, child := range node.Children {
+		record.Children = append(record.Children, child.ID)
+	}
+
+	return json.Marshal(record)
+}
@@ -0,0 +1,81 @@
+package structure
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// JSONFileStore keeps the whole metadata in a single JSON file, which is rewritten on every save.
+type JSONFileStore struct {
+	path string
+	lock *FileLock
+}
+
+func NewJSONFileStore(path string) *JSONFileStore {
+	return &JSONFileStore{
+		path: path,
+		lock: NewFileLock(path + lockFileSuffix),
+	}
+}
+
+func (jfs *JSONFileStore) Path() string {
+	return jfs.path
+}
+
+func (jfs *JSONFileStore) Lock() error {
+	return jfs.lock.Lock()
+}
+
+func (jfs *JSONFileStore) Unlock() error {
+	return jfs.lock.Unlock()
+}
+
+func (jfs *JSONFileStore) KeepsWorkspaceTrees() bool {
+	return false
+}
+
+// PutNode does nothing, the nodes are written with the whole metadata on save.
+func (jfs *JSONFileStore) PutNode(node *Node) error {
+	return nil
+}
+
+// DeleteNode does nothing, the nodes are written with the whole metadata on save.
+func (jfs *JSONFileStore) DeleteNode(id string) error {
+	return nil
+}
+
+func (jfs *JSONFileStore) Load(mmf *MetadataNoteWolfyFileHandle) error {
+	file, err := os.OpenFile(jfs.path, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+	mmf.Version = CurrentSchemaVersion
+	if len(content) == 0 {
+		return nil
+	}
+
+	content, err = migrateMetadata(jfs.path, content)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, mmf)
+}
+
+func (jfs *JSONFileStore) Save(mmf *MetadataNoteWolfyFileHandle) error {
+	mmf.Version = CurrentSchemaVersion
+	data, err := json.Marshal(mmf)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	return writeFileAtomically(jfs.path, data, 0644)
+}
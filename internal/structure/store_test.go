@@ -0,0 +1,309 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
+)
+
+func createPopulatedHandle(t *testing.T, config *structure.Config) *structure.MetadataNoteWolfyFileHandle {
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+
+	err = mmf.AddNewWorkspace("testA", "/tmp/A")
+	assert.NoError(t, err)
+	childNode := structure.NewNode("child", "/tmp/A/child")
+	err = mmf.AddChild(childNode)
+	assert.NoError(t, err)
+	childNode.Children = append(childNode.Children, structure.NewNode("grandchild", "/tmp/A/child/grandchild"))
+	mmf.AddMarkdownToNode(childNode, structure.NewMarkdown("note.md"))
+	mmf.SetActiveNode(childNode.ID)
+	err = mmf.AddNewWorkspace("testB", "/tmp/B")
+	assert.NoError(t, err)
+	err = mmf.Save()
+	assert.NoError(t, err)
+
+	return mmf
+}
+
+func TestBoltStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.db", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+		Backend:          structure.BoltBackend,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf := createPopulatedHandle(t, config)
+
+	actMmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	assert.Equal(t, mmf.Workspaces, actMmf.Workspaces)
+	assert.Equal(t, mmf.ActiveWorkspace, actMmf.ActiveWorkspace)
+	assert.Equal(t, mmf.ActiveNode, actMmf.ActiveNode)
+	assert.Equal(t, mmf.PreviousNode, actMmf.PreviousNode)
+	assert.Equal(t, structure.CurrentSchemaVersion, actMmf.Version)
+
+	// Records of deleted nodes have to disappear from the store
+	err = actMmf.DeleteChild(actMmf.Workspaces[0].Children[0], actMmf.Workspaces[0].Children[0].Children[0].ID)
+	assert.NoError(t, err)
+	actMmf.Workspaces = actMmf.Workspaces[:1]
+	err = actMmf.Save()
+	assert.NoError(t, err)
+
+	err = mmf.Lock()
+	assert.NoError(t, err)
+	defer mmf.Unlock()
+	assert.Equal(t, 1, len(mmf.Workspaces))
+	assert.Empty(t, mmf.Workspaces[0].Children[0].Children)
+	assert.Equal(t, "note.md", mmf.Workspaces[0].Children[0].Markdowns[0].Filename)
+}
+
+func TestBoltStoreSavesOnlyChangedNodes(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.db", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+		Backend:          structure.BoltBackend,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf := createPopulatedHandle(t, config)
+
+	// The record of a node that does not change is not written again, so a modification stays in the store
+	workspaceB := mmf.Workspaces[1]
+	db, err := bolt.Open(metadataFilePath, 0644, nil)
+	assert.NoError(t, err)
+	err = db.Update(func(tx *bolt.Tx) error {
+		nodes := tx.Bucket([]byte("nodes"))
+		record := bytes.Replace(nodes.Get([]byte(workspaceB.ID)), []byte(`"testB"`), []byte(`"unchanged"`), 1)
+		return nodes.Put([]byte(workspaceB.ID), record)
+	})
+	assert.NoError(t, err)
+	err = db.Close()
+	assert.NoError(t, err)
+
+	childNode := mmf.Workspaces[0].Children[0]
+	err = mmf.DeleteChild(childNode, childNode.Children[0].ID)
+	assert.NoError(t, err)
+	mmf.AddMarkdownToNode(childNode, structure.NewMarkdown("other.md"))
+	err = mmf.Save()
+	assert.NoError(t, err)
+
+	actMmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "unchanged", actMmf.Workspaces[1].Name)
+	assert.Empty(t, actMmf.Workspaces[0].Children[0].Children)
+	assert.Len(t, actMmf.Workspaces[0].Children[0].Markdowns, 2)
+
+	// The record of the deleted node is removed from the store
+	db, err = bolt.Open(metadataFilePath, 0644, nil)
+	assert.NoError(t, err)
+	defer db.Close()
+	err = db.View(func(tx *bolt.Tx) error {
+		assert.Equal(t, 3, tx.Bucket([]byte("nodes")).Stats().KeyN)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestBoltStoreRewritesMigratedNodes(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.db", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+		Backend:          structure.BoltBackend,
+	}
+	defer CleanUpFile(metadataFilePath)
+	backupFilePath := structure.BackupFilePath(metadataFilePath, 3)
+	defer os.Remove(backupFilePath)
+
+	mmf := createPopulatedHandle(t, config)
+	childID := mmf.Workspaces[0].Children[0].ID
+	db, err := bolt.Open(metadataFilePath, 0644, nil)
+	assert.NoError(t, err)
+	err = db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("meta")).Put([]byte("version"), []byte("3"))
+	})
+	assert.NoError(t, err)
+	err = db.Close()
+	assert.NoError(t, err)
+
+	// Every record is written again after a migration, although no node has been changed
+	actMmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	assert.FileExists(t, backupFilePath)
+	err = actMmf.Save()
+	assert.NoError(t, err)
+
+	db, err = bolt.Open(metadataFilePath, 0644, nil)
+	assert.NoError(t, err)
+	defer db.Close()
+	err = db.View(func(tx *bolt.Tx) error {
+		assert.Equal(t, fmt.Sprint(structure.CurrentSchemaVersion), string(tx.Bucket([]byte("meta")).Get([]byte("version"))))
+		assert.Contains(t, string(tx.Bucket([]byte("nodes")).Get([]byte(childID))), `"title":"note"`)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestMigrateStore(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	sourceConfig := &structure.Config{
+		MetadataFilePath: fmt.Sprintf("./.notewolfy-%s.json", fileID),
+	}
+	defer CleanUpFile(sourceConfig.MetadataFilePath)
+	targetConfig := &structure.Config{
+		MetadataFilePath: fmt.Sprintf("./.notewolfy-%s.db", fileID),
+		Backend:          structure.BoltBackend,
+	}
+	defer CleanUpFile(targetConfig.MetadataFilePath)
+
+	sourceMmf := createPopulatedHandle(t, sourceConfig)
+
+	sourceStore, err := structure.NewMetadataStore(sourceConfig)
+	assert.NoError(t, err)
+	targetStore, err := structure.NewMetadataStore(targetConfig)
+	assert.NoError(t, err)
+	err = structure.MigrateStore(sourceStore, targetStore)
+	assert.NoError(t, err)
+
+	targetMmf, err := structure.NewMetadataNoteWolfyFileHandle(targetConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, sourceMmf.Workspaces, targetMmf.Workspaces)
+	assert.Equal(t, sourceMmf.ActiveNode, targetMmf.ActiveNode)
+
+	err = structure.MigrateStore(sourceStore, targetStore)
+	if assert.Error(t, err) {
+		expError := fmt.Errorf("target store %s already contains workspaces", targetConfig.MetadataFilePath)
+		assert.Equal(t, expError, err)
+	}
+}
+
+func TestBoltStoreKeepsWorkspaceTrees(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	sourceConfig := &structure.Config{
+		MetadataFilePath: fmt.Sprintf("./.notewolfy-%s.json", fileID),
+	}
+	defer CleanUpFile(sourceConfig.MetadataFilePath)
+	targetConfig := &structure.Config{
+		MetadataFilePath: fmt.Sprintf("./.notewolfy-%s.db", fileID),
+		Backend:          structure.BoltBackend,
+	}
+	defer CleanUpFile(targetConfig.MetadataFilePath)
+
+	// The tree of the JSON backend lives in the workspace root and is copied into the database
+	workspacePath := t.TempDir()
+	sourceMmf, err := structure.NewMetadataNoteWolfyFileHandle(sourceConfig)
+	assert.NoError(t, err)
+	err = sourceMmf.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	err = sourceMmf.AddChild(structure.NewNode("child", filepath.Join(workspacePath, "child")))
+	assert.NoError(t, err)
+	err = sourceMmf.Save()
+	assert.NoError(t, err)
+	treeFilePath := structure.WorkspaceTreeFilePath(workspacePath)
+	assert.FileExists(t, treeFilePath)
+
+	sourceStore, err := structure.NewMetadataStore(sourceConfig)
+	assert.NoError(t, err)
+	targetStore, err := structure.NewMetadataStore(targetConfig)
+	assert.NoError(t, err)
+	err = structure.MigrateStore(sourceStore, targetStore)
+	assert.NoError(t, err)
+	err = os.Remove(treeFilePath)
+	assert.NoError(t, err)
+
+	targetMmf, err := structure.NewMetadataNoteWolfyFileHandle(targetConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, sourceMmf.Workspaces, targetMmf.Workspaces)
+
+	// Saving with the bolt backend does not write the tree into the workspace root
+	err = targetMmf.AddChild(structure.NewNode("other", filepath.Join(workspacePath, "other")))
+	assert.NoError(t, err)
+	err = targetMmf.Save()
+	assert.NoError(t, err)
+	assert.NoFileExists(t, treeFilePath)
+	err = targetMmf.Lock()
+	assert.NoError(t, err)
+	defer targetMmf.Unlock()
+	assert.Len(t, targetMmf.Workspaces[0].Children, 2)
+}
+
+func TestMigrateStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	jsonConfig := &structure.Config{
+		MetadataFilePath: fmt.Sprintf("./.notewolfy-%s.json", fileID),
+	}
+	defer CleanUpFile(jsonConfig.MetadataFilePath)
+	boltConfig := &structure.Config{
+		MetadataFilePath: fmt.Sprintf("./.notewolfy-%s.db", fileID),
+		Backend:          structure.BoltBackend,
+	}
+	defer CleanUpFile(boltConfig.MetadataFilePath)
+
+	workspacePath := t.TempDir()
+	jsonMmf, err := structure.NewMetadataNoteWolfyFileHandle(jsonConfig)
+	assert.NoError(t, err)
+	err = jsonMmf.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	err = jsonMmf.Save()
+	assert.NoError(t, err)
+
+	jsonStore, err := structure.NewMetadataStore(jsonConfig)
+	assert.NoError(t, err)
+	boltStore, err := structure.NewMetadataStore(boltConfig)
+	assert.NoError(t, err)
+	err = structure.MigrateStore(jsonStore, boltStore)
+	assert.NoError(t, err)
+
+	// A note added with the bolt backend is not part of the tree file that is left in the workspace root
+	boltMmf, err := structure.NewMetadataNoteWolfyFileHandle(boltConfig)
+	assert.NoError(t, err)
+	boltMmf.AddMarkdownToNode(boltMmf.Workspaces[0], structure.NewMarkdown("notes.md"))
+	err = boltMmf.Save()
+	assert.NoError(t, err)
+
+	err = os.Remove(jsonConfig.MetadataFilePath)
+	assert.NoError(t, err)
+	err = structure.MigrateStore(boltStore, jsonStore)
+	assert.NoError(t, err)
+	actMmf, err := structure.NewMetadataNoteWolfyFileHandle(jsonConfig)
+	assert.NoError(t, err)
+	if assert.Len(t, actMmf.Workspaces[0].Markdowns, 1) {
+		assert.Equal(t, "notes.md", actMmf.Workspaces[0].Markdowns[0].Filename)
+	}
+}
+
+func TestNewMetadataStoreWithUnknownBackend(t *testing.T) {
+	t.Parallel()
+
+	_, err := structure.NewMetadataStore(&structure.Config{MetadataFilePath: "./.notewolfy", Backend: "sqlite"})
+	if assert.Error(t, err) {
+		expError := errors.New("unknown metadata backend 'sqlite', supported backends are 'json' and 'bolt'")
+		assert.Equal(t, expError, err)
+	}
+}
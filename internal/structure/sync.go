@@ -0,0 +1,404 @@
+package structure
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const MarkdownFileExtension = ".md"
+
+type SyncChangeKind string
+
+const (
+	SyncAdded   SyncChangeKind = "added"
+	SyncMissing SyncChangeKind = "missing"
+	SyncMoved   SyncChangeKind = "moved"
+)
+
+type SyncChange struct {
+	Kind    SyncChangeKind
+	IsNode  bool
+	Path    string
+	OldPath string
+}
+
+type SyncReport struct {
+	Workspace *Node
+	Changes   []*SyncChange
+	// Skipped holds the directories and markdown files that are left out, since their names are no valid names.
+	Skipped []string
+
+	synced            *Node
+	hasRefreshedNotes bool
+}
+
+func (sr *SyncReport) HasChanges() bool {
+	return len(sr.Changes) != 0
+}
+
+// HasRefreshedNotes reports whether the metadata of a note, e.g. its title or word count, differs from the file.
+// Refreshed notes are not listed as changes, since the structure of the workspace is still in sync.
+func (sr *SyncReport) HasRefreshedNotes() bool {
+	return sr.hasRefreshedNotes
+}
+
+func (sr *SyncReport) Print() {
+	fmt.Printf("\r\nWorkspace '%s':\n", sr.Workspace.Name)
+	if !sr.HasChanges() {
+		fmt.Println("\r Everything is in sync")
+	}
+	for _, change := range sr.Changes {
+		resourceType := "markdown"
+		if change.IsNode {
+			resourceType = "node"
+		}
+		relativePath := sr.relativePath(change.Path)
+		switch change.Kind {
+		case SyncAdded:
+			fmt.Printf("\r + added %s %s\n", resourceType, relativePath)
+		case SyncMissing:
+			fmt.Printf("\r - missing %s %s\n", resourceType, relativePath)
+		case SyncMoved:
+			fmt.Printf("\r ~ moved %s %s -> %s\n", resourceType, sr.relativePath(change.OldPath), relativePath)
+		}
+	}
+	for _, path := range sr.Skipped {
+		fmt.Printf("\r ! skipped %s, its name does not match the regex %s\n", sr.relativePath(path), NamePattern)
+	}
+}
+
+func (sr *SyncReport) relativePath(path string) string {
+	relativePath, err := filepath.Rel(sr.Workspace.Path, path)
+	if err != nil {
+		return path
+	}
+
+	return relativePath
+}
+
+type diskEntries struct {
+	directories map[string]bool
+	markdowns   map[string]bool
+	skipped     []string
+}
+
+type metadataEntries struct {
+	nodes     map[string]*Node
+	markdowns map[string]*Node
+}
+
+func scanWorkspaceDirectory(workspacePath string) (*diskEntries, error) {
+	entries := &diskEntries{
+		directories: make(map[string]bool),
+		markdowns:   make(map[string]bool),
+	}
+	ignoreMatcher, err := LoadIgnoreFile(workspacePath)
+	if err != nil {
+		return nil, err
+	}
+	err = filepath.WalkDir(workspacePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == workspacePath {
+			return nil
+		}
+		relativePath, err := filepath.Rel(workspacePath, path)
+		if err != nil {
+			return err
+		}
+		// Hidden entries are skipped, which includes the .notewolfy directory of the workspace.
+		if strings.HasPrefix(entry.Name(), ".") || ignoreMatcher.IsIgnored(filepath.ToSlash(relativePath), entry.IsDir()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() && !IsValidName(entry.Name()) {
+			entries.skipped = append(entries.skipped, path)
+			return filepath.SkipDir
+		}
+		if entry.IsDir() {
+			entries.directories[path] = true
+			return nil
+		}
+		if entry.Type().IsRegular() && filepath.Ext(entry.Name()) == MarkdownFileExtension {
+			if !IsValidName(strings.TrimSuffix(entry.Name(), MarkdownFileExtension)) {
+				entries.skipped = append(entries.skipped, path)
+				return nil
+			}
+			entries.markdowns[path] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func collectMetadataEntries(workspace *Node) *metadataEntries {
+	entries := &metadataEntries{
+		nodes:     make(map[string]*Node),
+		markdowns: make(map[string]*Node),
+	}
+	queue := NewQueue[*Node]()
+	queue.Add(workspace)
+	for queue.Len() > 0 {
+		currentNode := queue.Drop()
+		entries.nodes[currentNode.Path] = currentNode
+		for _, markdown := range currentNode.Markdowns {
+			entries.markdowns[filepath.Join(currentNode.Path, markdown.Filename)] = currentNode
+		}
+		for _, child := range currentNode.Children {
+			queue.Add(child)
+		}
+	}
+
+	return entries
+}
+
+func sortedKeys(set map[string]bool) []string {
+	var keys []string
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// pairMoves matches missing and added paths that share the same base name, a pair only counts as a move
+// if the base name is unique on both sides, otherwise the entries stay added and missing.
+func pairMoves(missingPaths []string, addedPaths []string) map[string]string {
+	missingByName := make(map[string][]string)
+	for _, path := range missingPaths {
+		missingByName[filepath.Base(path)] = append(missingByName[filepath.Base(path)], path)
+	}
+	addedByName := make(map[string][]string)
+	for _, path := range addedPaths {
+		addedByName[filepath.Base(path)] = append(addedByName[filepath.Base(path)], path)
+	}
+
+	moves := make(map[string]string)
+	for name, missing := range missingByName {
+		added := addedByName[name]
+		if len(missing) == 1 && len(added) == 1 {
+			moves[missing[0]] = added[0]
+		}
+	}
+
+	return moves
+}
+
+func findChildIndex(parentNode *Node, id string) int {
+	for index, child := range parentNode.Children {
+		if child.ID == id {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func findParentInTree(root *Node, id string) *Node {
+	queue := NewQueue[*Node]()
+	queue.Add(root)
+	for queue.Len() > 0 {
+		currentNode := queue.Drop()
+		for _, child := range currentNode.Children {
+			if child.ID == id {
+				return currentNode
+			}
+			queue.Add(child)
+		}
+	}
+
+	return nil
+}
+
+func ensureNodePath(report *SyncReport, metadata *metadataEntries, path string) *Node {
+	if node := metadata.nodes[path]; node != nil {
+		return node
+	}
+	parentNode := ensureNodePath(report, metadata, filepath.Dir(path))
+	node := NewNode(filepath.Base(path), path)
+	parentNode.Children = append(parentNode.Children, node)
+	metadata.nodes[path] = node
+	report.Changes = append(report.Changes, &SyncChange{Kind: SyncAdded, IsNode: true, Path: path})
+
+	return node
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) DiffWorkspace(workspace *Node) (*SyncReport, error) {
+	disk, err := scanWorkspaceDirectory(workspace.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SyncReport{
+		Workspace: workspace,
+		Skipped:   disk.skipped,
+		synced:    workspace.Clone(),
+	}
+
+	// Directory moves are applied first, since moving a node also moves everything below it.
+	for {
+		metadata := collectMetadataEntries(report.synced)
+		var missingDirectories []string
+		for path, node := range metadata.nodes {
+			if node != report.synced && !disk.directories[path] {
+				missingDirectories = append(missingDirectories, path)
+			}
+		}
+		var addedDirectories []string
+		for _, path := range sortedKeys(disk.directories) {
+			if metadata.nodes[path] == nil {
+				addedDirectories = append(addedDirectories, path)
+			}
+		}
+
+		moves := pairMoves(missingDirectories, addedDirectories)
+		oldPathsByNewPath := make(map[string]string)
+		newPaths := make(map[string]bool)
+		for oldPath, newPath := range moves {
+			if !strings.HasPrefix(newPath, oldPath+string(filepath.Separator)) {
+				oldPathsByNewPath[newPath] = oldPath
+				newPaths[newPath] = true
+			}
+		}
+		if len(newPaths) == 0 {
+			break
+		}
+		// The shallowest target is moved first, new parent directories of the target are created on the way.
+		newPath := sortedKeys(newPaths)[0]
+		oldPath := oldPathsByNewPath[newPath]
+		newParentNode := ensureNodePath(report, metadata, filepath.Dir(newPath))
+		movedNode := metadata.nodes[oldPath]
+		oldParentNode := findParentInTree(report.synced, movedNode.ID)
+		index := findChildIndex(oldParentNode, movedNode.ID)
+		oldParentNode.Children = append(oldParentNode.Children[:index], oldParentNode.Children[index+1:]...)
+		movedNode.Name = filepath.Base(newPath)
+		movedNode.SetPath(newPath)
+		newParentNode.Children = append(newParentNode.Children, movedNode)
+		report.Changes = append(report.Changes, &SyncChange{Kind: SyncMoved, IsNode: true, Path: newPath, OldPath: oldPath})
+	}
+
+	// Missing directories are removed together with their subtree, added directories are created top-down.
+	metadata := collectMetadataEntries(report.synced)
+	var missingDirectories []string
+	for path, node := range metadata.nodes {
+		if node != report.synced && !disk.directories[path] {
+			missingDirectories = append(missingDirectories, path)
+		}
+	}
+	sort.Strings(missingDirectories)
+	removedPrefixes := []string{}
+	for _, path := range missingDirectories {
+		isBelowRemovedNode := false
+		for _, prefix := range removedPrefixes {
+			if strings.HasPrefix(path, prefix+string(filepath.Separator)) {
+				isBelowRemovedNode = true
+				break
+			}
+		}
+		if isBelowRemovedNode {
+			continue
+		}
+		node := metadata.nodes[path]
+		parentNode := findParentInTree(report.synced, node.ID)
+		index := findChildIndex(parentNode, node.ID)
+		parentNode.Children = append(parentNode.Children[:index], parentNode.Children[index+1:]...)
+		removedPrefixes = append(removedPrefixes, path)
+		report.Changes = append(report.Changes, &SyncChange{Kind: SyncMissing, IsNode: true, Path: path})
+	}
+	for _, path := range sortedKeys(disk.directories) {
+		if metadata.nodes[path] != nil {
+			continue
+		}
+		parentNode := metadata.nodes[filepath.Dir(path)]
+		if parentNode == nil {
+			continue
+		}
+		childNode := NewNode(filepath.Base(path), path)
+		parentNode.Children = append(parentNode.Children, childNode)
+		metadata.nodes[path] = childNode
+		report.Changes = append(report.Changes, &SyncChange{Kind: SyncAdded, IsNode: true, Path: path})
+	}
+
+	// Markdown files are compared last, against the already reconciled node tree.
+	metadata = collectMetadataEntries(report.synced)
+	var missingMarkdowns []string
+	for path := range metadata.markdowns {
+		if !disk.markdowns[path] {
+			missingMarkdowns = append(missingMarkdowns, path)
+		}
+	}
+	sort.Strings(missingMarkdowns)
+	var addedMarkdowns []string
+	for _, path := range sortedKeys(disk.markdowns) {
+		if metadata.markdowns[path] == nil {
+			addedMarkdowns = append(addedMarkdowns, path)
+		}
+	}
+
+	markdownMoves := pairMoves(missingMarkdowns, addedMarkdowns)
+	movedTargets := make(map[string]bool)
+	for _, path := range missingMarkdowns {
+		node := metadata.markdowns[path]
+		markdownName := strings.TrimSuffix(filepath.Base(path), MarkdownFileExtension)
+		markdown := mmf.FindMarkdown(node, markdownName)
+		mmf.DeleteMarkdownFromNode(node, markdownName)
+
+		newPath, isMoved := markdownMoves[path]
+		newNode := metadata.nodes[filepath.Dir(newPath)]
+		if isMoved && newNode != nil {
+			markdown.Filename = filepath.Base(newPath)
+			newNode.Markdowns = append(newNode.Markdowns, markdown)
+			movedTargets[newPath] = true
+			report.Changes = append(report.Changes, &SyncChange{Kind: SyncMoved, Path: newPath, OldPath: path})
+			continue
+		}
+		report.Changes = append(report.Changes, &SyncChange{Kind: SyncMissing, Path: path})
+	}
+	for _, path := range addedMarkdowns {
+		if movedTargets[path] {
+			continue
+		}
+		node := metadata.nodes[filepath.Dir(path)]
+		if node == nil {
+			continue
+		}
+		node.Markdowns = append(node.Markdowns, NewMarkdown(filepath.Base(path)))
+		report.Changes = append(report.Changes, &SyncChange{Kind: SyncAdded, Path: path})
+	}
+
+	for _, node := range collectMetadataEntries(report.synced).nodes {
+		for _, markdown := range node.Markdowns {
+			isRefreshed, err := markdown.Refresh(filepath.Join(node.Path, markdown.Filename))
+			if err == nil && isRefreshed {
+				report.hasRefreshedNotes = true
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) ApplySyncReport(report *SyncReport) {
+	workspace := report.Workspace
+	workspace.Children = report.synced.Children
+	workspace.Markdowns = report.synced.Markdowns
+	mmf.markSubtreeChanged(workspace)
+
+	if mmf.ActiveWorkspace == workspace.Name && mmf.FindNode(mmf.ActiveNode) == nil {
+		mmf.ActiveNode = workspace.ID
+	}
+	if mmf.ActiveWorkspace == workspace.Name && mmf.FindNode(mmf.PreviousNode) == nil {
+		mmf.PreviousNode = ""
+	}
+}
@@ -0,0 +1,99 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffAndApplyWorkspace(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+
+	// Metadata: /archive/old.md, /drafts/moved.md, /gone/ and /projects/sub/
+	workspacePath, err := filepath.Abs(fmt.Sprintf("./tmp-%s", fileID))
+	assert.NoError(t, err)
+	defer os.RemoveAll(workspacePath)
+	workspaceNode := structure.NewNode("Workspace", workspacePath)
+	archiveNode := structure.NewNode("archive", filepath.Join(workspacePath, "archive"))
+	archiveNode.Markdowns = append(archiveNode.Markdowns, structure.NewMarkdown("old.md"))
+	draftsNode := structure.NewNode("drafts", filepath.Join(workspacePath, "drafts"))
+	movedMarkdown := structure.NewMarkdown("moved.md")
+	draftsNode.Markdowns = append(draftsNode.Markdowns, movedMarkdown)
+	goneNode := structure.NewNode("gone", filepath.Join(workspacePath, "gone"))
+	projectsNode := structure.NewNode("projects", filepath.Join(workspacePath, "projects"))
+	subNode := structure.NewNode("sub", filepath.Join(projectsNode.Path, "sub"))
+	projectsNode.Children = append(projectsNode.Children, subNode)
+	workspaceNode.Children = append(workspaceNode.Children, archiveNode, draftsNode, goneNode, projectsNode)
+	mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
+	mmf.ActiveWorkspace = workspaceNode.Name
+	mmf.ActiveNode = goneNode.ID
+
+	// Disk: /archive/ without old.md, /drafts/, /final/moved.md, /new/new.md, /projects/ and /renamed/sub/,
+	// directories with names that are no valid node names are skipped together with their content, just like markdown files
+	// with names that are no valid names
+	for _, directory := range []string{"archive", "drafts", "final", "new", "projects", "renamed/sub", ".hidden", "my notes", "new/v1.2"} {
+		err = os.MkdirAll(filepath.Join(workspacePath, directory), os.ModePerm)
+		assert.NoError(t, err)
+	}
+	for _, file := range []string{"final/moved.md", "new/new.md", "new/ignored.txt", ".hidden/hidden.md", "my notes/skipped.md", "new/meeting-notes.md"} {
+		err = os.WriteFile(filepath.Join(workspacePath, file), []byte("# note"), 0644)
+		assert.NoError(t, err)
+	}
+
+	report, err := mmf.DiffWorkspace(workspaceNode)
+	assert.NoError(t, err)
+	assert.True(t, report.HasChanges())
+
+	expChanges := []*structure.SyncChange{
+		{Kind: structure.SyncAdded, IsNode: true, Path: filepath.Join(workspacePath, "renamed")},
+		{Kind: structure.SyncMoved, IsNode: true, Path: filepath.Join(workspacePath, "renamed", "sub"), OldPath: filepath.Join(workspacePath, "projects", "sub")},
+		{Kind: structure.SyncMissing, IsNode: true, Path: filepath.Join(workspacePath, "gone")},
+		{Kind: structure.SyncAdded, IsNode: true, Path: filepath.Join(workspacePath, "final")},
+		{Kind: structure.SyncAdded, IsNode: true, Path: filepath.Join(workspacePath, "new")},
+		{Kind: structure.SyncMissing, Path: filepath.Join(workspacePath, "archive", "old.md")},
+		{Kind: structure.SyncMoved, Path: filepath.Join(workspacePath, "final", "moved.md"), OldPath: filepath.Join(workspacePath, "drafts", "moved.md")},
+		{Kind: structure.SyncAdded, Path: filepath.Join(workspacePath, "new", "new.md")},
+	}
+	assert.Equal(t, expChanges, report.Changes)
+	assert.Equal(t, []string{filepath.Join(workspacePath, "my notes"), filepath.Join(workspacePath, "new", "meeting-notes.md"), filepath.Join(workspacePath, "new", "v1.2")}, report.Skipped)
+	assert.Equal(t, 4, len(workspaceNode.Children))
+
+	mmf.ApplySyncReport(report)
+	assert.Equal(t, workspaceNode.ID, mmf.ActiveNode)
+
+	finalNode, err := mmf.ResolveNodePath("/final")
+	assert.NoError(t, err)
+	assert.Equal(t, movedMarkdown.ID, finalNode.Markdowns[0].ID)
+	syncedArchiveNode, err := mmf.ResolveNodePath("/archive")
+	assert.NoError(t, err)
+	assert.Empty(t, syncedArchiveNode.Markdowns)
+	syncedSubNode, err := mmf.ResolveNodePath("/renamed/sub")
+	assert.NoError(t, err)
+	assert.Equal(t, subNode.ID, syncedSubNode.ID)
+	newNode, err := mmf.ResolveNodePath("/new")
+	assert.NoError(t, err)
+	assert.Equal(t, "new.md", newNode.Markdowns[0].Filename)
+	_, err = mmf.ResolveNodePath("/gone")
+	assert.Error(t, err)
+
+	report, err = mmf.DiffWorkspace(workspaceNode)
+	assert.NoError(t, err)
+	assert.False(t, report.HasChanges())
+}
@@ -0,0 +1,178 @@
+package structure
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterTagsKey = "tags"
+
+// NoteReference points to a markdown file together with its node and the node path in the workspace.
+type NoteReference struct {
+	Workspace string
+	NodePath  string
+	Node      *Node
+	Markdown  *Markdown
+}
+
+func (nr *NoteReference) FilePath() string {
+	return filepath.Join(nr.Node.Path, nr.Markdown.Filename)
+}
+
+func workspaceNotes(workspace *Node) []*NoteReference {
+	var notes []*NoteReference
+	WalkNodePaths(workspace, func(nodePath string, node *Node) {
+		for _, markdown := range node.Markdowns {
+			notes = append(notes, &NoteReference{Workspace: workspace.Name, NodePath: nodePath, Node: node, Markdown: markdown})
+		}
+	})
+
+	return notes
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) ActiveWorkspaceNotes() []*NoteReference {
+	activeWorkspace := mmf.FindActiveWorkspace()
+	if activeWorkspace == nil {
+		return nil
+	}
+
+	return workspaceNotes(activeWorkspace)
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) AllWorkspaceNotes() []*NoteReference {
+	var notes []*NoteReference
+	for _, workspace := range mmf.Workspaces {
+		notes = append(notes, workspaceNotes(workspace)...)
+	}
+
+	return notes
+}
+
+// CountTags returns the number of notes per tag in the active workspace.
+func (mmf *MetadataNoteWolfyFileHandle) CountTags() map[string]int {
+	tagCounts := make(map[string]int)
+	for _, note := range mmf.ActiveWorkspaceNotes() {
+		for _, tag := range note.Markdown.Tags {
+			tagCounts[tag]++
+		}
+	}
+
+	return tagCounts
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) FindNotesByTag(tag string) []*NoteReference {
+	tag = NormalizeTag(tag)
+	var notes []*NoteReference
+	for _, note := range mmf.ActiveWorkspaceNotes() {
+		if slices.Contains(note.Markdown.Tags, tag) {
+			notes = append(notes, note)
+		}
+	}
+
+	return notes
+}
+
+// TagMarkdown adds the tags to the front matter of the markdown file and refreshes its metadata.
+func (mmf *MetadataNoteWolfyFileHandle) TagMarkdown(entry *JournalEntry, node *Node, markdown *Markdown, tags []string) error {
+	return mmf.updateMarkdownTags(entry, node, markdown, func(currentTags []string) []string {
+		return normalizeTags(append(currentTags, tags...))
+	})
+}
+
+// UntagMarkdown removes the tags from the front matter of the markdown file and refreshes its metadata.
+func (mmf *MetadataNoteWolfyFileHandle) UntagMarkdown(entry *JournalEntry, node *Node, markdown *Markdown, tags []string) error {
+	removedTags := normalizeTags(tags)
+	return mmf.updateMarkdownTags(entry, node, markdown, func(currentTags []string) []string {
+		return slices.DeleteFunc(currentTags, func(tag string) bool {
+			return slices.Contains(removedTags, tag)
+		})
+	})
+}
+
+// updateMarkdownTags starts from the tags of the front matter, since the note could have been edited since the last refresh.
+func (mmf *MetadataNoteWolfyFileHandle) updateMarkdownTags(entry *JournalEntry, node *Node, markdown *Markdown, update func(currentTags []string) []string) error {
+	path := filepath.Join(node.Path, markdown.Filename)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	frontMatter, _ := ParseFrontMatter(content)
+	currentTags := normalizeTags(frontMatter.Tags)
+	updatedTags := update(slices.Clone(currentTags))
+	if !slices.Equal(currentTags, updatedTags) {
+		updatedContent, err := SetFrontMatterTags(content, updatedTags)
+		if err != nil {
+			return err
+		}
+		entry.TrackFile(path)
+		if err := os.WriteFile(path, updatedContent, 0644); err != nil {
+			return err
+		}
+	}
+
+	entry.TrackMarkdown(markdown.ID)
+	_, err = mmf.RefreshMarkdown(node, markdown)
+
+	return err
+}
+
+// SetFrontMatterTags replaces the tags of the front matter and keeps all other fields,
+// a front matter is added if the note does not have one and removed if nothing is left in it.
+func SetFrontMatterTags(content []byte, tags []string) ([]byte, error) {
+	rawFrontMatter, body := SplitFrontMatter(content)
+	document := &yaml.Node{}
+	if err := yaml.Unmarshal(rawFrontMatter, document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 {
+		document = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	mapping := document.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, errors.New("front matter is not a mapping")
+	}
+
+	tagsNode := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, tag := range tags {
+		tagsNode.Content = append(tagsNode.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: tag})
+	}
+	isReplaced := false
+	for index := 0; index < len(mapping.Content); index += 2 {
+		if mapping.Content[index].Value != frontMatterTagsKey {
+			continue
+		}
+		if len(tags) == 0 {
+			mapping.Content = slices.Delete(mapping.Content, index, index+2)
+		} else {
+			mapping.Content[index+1] = tagsNode
+		}
+		isReplaced = true
+		break
+	}
+	if !isReplaced && len(tags) != 0 {
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: frontMatterTagsKey}, tagsNode)
+	}
+	if len(mapping.Content) == 0 {
+		return body, nil
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(frontMatterDelimiter + "\n")
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	buffer.WriteString(frontMatterDelimiter + "\n")
+	buffer.Write(body)
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,52 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFrontMatterTags(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content string
+		tags    []string
+		want    string
+	}{
+		"note without front matter": {
+			content: "# Weekly\n",
+			tags:    []string{"home", "todo"},
+			want:    "---\ntags: [home, todo]\n---\n# Weekly\n",
+		},
+		"note with other fields": {
+			content: "---\ntitle: Weekly\ntags:\n  - work\nauthor: me\n---\n# Weekly\n",
+			tags:    []string{"home"},
+			want:    "---\ntitle: Weekly\ntags: [home]\nauthor: me\n---\n# Weekly\n",
+		},
+		"removing the last tag keeps other fields": {
+			content: "---\ntitle: Weekly\ntags: [home]\n---\n# Weekly\n",
+			tags:    nil,
+			want:    "---\ntitle: Weekly\n---\n# Weekly\n",
+		},
+		"removing the last field removes the front matter": {
+			content: "---\ntags: [home]\n---\n# Weekly\n",
+			tags:    nil,
+			want:    "# Weekly\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			content, err := structure.SetFrontMatterTags([]byte(tc.content), tc.tags)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, string(content))
+		})
+	}
+
+	_, err := structure.SetFrontMatterTags([]byte("---\n- home\n---\n"), []string{"home"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,332 @@
+package structure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/RaphSku/notewolfy/internal/utility"
+	"github.com/google/uuid"
+)
+
+const (
+	TrashDirName         = "trash"
+	deletedWorkspacesDir = ".trash"
+)
+
+type TrashItemKind string
+
+const (
+	TrashMarkdown  TrashItemKind = "markdown"
+	TrashNode      TrashItemKind = "node"
+	TrashWorkspace TrashItemKind = "workspace"
+)
+
+// TrashItem records a deleted resource together with its original location. For markdown files and nodes
+// the location is the node path of the containing node, for workspaces it is the path of the workspace root.
+type TrashItem struct {
+	ID           string        `json:"id"`
+	Kind         TrashItemKind `json:"kind"`
+	Name         string        `json:"name"`
+	OriginalPath string        `json:"originalpath"`
+	DeletedAt    time.Time     `json:"deletedat"`
+	Node         *Node         `json:"node,omitempty"`
+	Markdown     *Markdown     `json:"markdown,omitempty"`
+}
+
+func newTrashItem(kind TrashItemKind, name string, originalPath string) *TrashItem {
+	return &TrashItem{
+		ID:           uuid.New().String(),
+		Kind:         kind,
+		Name:         name,
+		OriginalPath: originalPath,
+		DeletedAt:    time.Now(),
+	}
+}
+
+func (ti *TrashItem) Clone() *TrashItem {
+	clonedItem := *ti
+	if ti.Node != nil {
+		clonedItem.Node = ti.Node.Clone()
+	}
+	if ti.Markdown != nil {
+		clonedItem.Markdown = ti.Markdown.Clone()
+	}
+
+	return &clonedItem
+}
+
+func removeTrashItem(items []*TrashItem, id string) []*TrashItem {
+	for index, item := range items {
+		if item.ID == id {
+			return append(items[:index], items[index+1:]...)
+		}
+	}
+
+	return items
+}
+
+// WorkspaceTrashDirPath is the trash area of a workspace, deleted markdown files and nodes are kept there.
+func WorkspaceTrashDirPath(workspacePath string) string {
+	return filepath.Join(workspacePath, WorkspaceMetadataDirName, TrashDirName)
+}
+
+// deletedWorkspacesDirPath is located next to the metadata file, since a deleted workspace takes its trash area with it.
+func (mmf *MetadataNoteWolfyFileHandle) deletedWorkspacesDirPath() string {
+	return mmf.Config.MetadataFilePath + deletedWorkspacesDir
+}
+
+// trashedFilePath returns the location of the file or directory of the item inside of the trash.
+func (mmf *MetadataNoteWolfyFileHandle) trashedFilePath(workspace *Node, item *TrashItem) string {
+	if item.Kind == TrashWorkspace {
+		return filepath.Join(mmf.deletedWorkspacesDirPath(), item.ID, item.Name)
+	}
+
+	return filepath.Join(WorkspaceTrashDirPath(workspace.Path), item.ID, item.Name)
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) moveTrackedPath(entry *JournalEntry, sourcePath string, targetPath string) error {
+	if err := utility.MovePath(sourcePath, targetPath); err != nil {
+		return err
+	}
+	entry.TrackMove(sourcePath, targetPath)
+
+	return nil
+}
+
+// MoveMarkdownToTrash moves the markdown file of the node in the active workspace into the trash of the workspace.
+func (mmf *MetadataNoteWolfyFileHandle) MoveMarkdownToTrash(entry *JournalEntry, node *Node, markdown *Markdown) error {
+	workspace := mmf.FindActiveWorkspace()
+	if workspace == nil {
+		return errors.New("no active workspace, seems like you have not created a workspace yet")
+	}
+	item := newTrashItem(TrashMarkdown, markdown.Filename, mmf.NodePath(node.ID))
+	item.Markdown = markdown.Clone()
+
+	entry.TrackMarkdown(markdown.ID)
+	entry.TrackTrashItem(item.ID)
+	if err := mmf.moveTrackedPath(entry, filepath.Join(node.Path, markdown.Filename), mmf.trashedFilePath(workspace, item)); err != nil {
+		return err
+	}
+	if err := mmf.DeleteMarkdownFromNode(node, strings.TrimSuffix(markdown.Filename, MarkdownFileExtension)); err != nil {
+		return err
+	}
+	workspace.Trash = append(workspace.Trash, item)
+	mmf.markNodeChanged(workspace)
+
+	return nil
+}
+
+// MoveNodeToTrash moves the node of the active workspace together with its subtree into the trash of the workspace.
+func (mmf *MetadataNoteWolfyFileHandle) MoveNodeToTrash(entry *JournalEntry, node *Node) error {
+	workspace := mmf.FindActiveWorkspace()
+	if workspace == nil {
+		return errors.New("no active workspace, seems like you have not created a workspace yet")
+	}
+	parentNode := mmf.FindParentNode(node.ID)
+	if parentNode == nil {
+		return fmt.Errorf("node '%s' has no parent node", node.Name)
+	}
+	item := newTrashItem(TrashNode, node.Name, mmf.NodePath(parentNode.ID))
+	item.Node = node.Clone()
+
+	entry.TrackNode(node.ID)
+	entry.TrackTrashItem(item.ID)
+	if err := mmf.moveTrackedPath(entry, node.Path, mmf.trashedFilePath(workspace, item)); err != nil {
+		return err
+	}
+	if err := mmf.DeleteChild(parentNode, node.ID); err != nil {
+		return err
+	}
+	workspace.Trash = append(workspace.Trash, item)
+	mmf.markNodeChanged(workspace)
+
+	return nil
+}
+
+// MoveWorkspaceToTrash moves the workspace root next to the metadata file and removes the workspace.
+func (mmf *MetadataNoteWolfyFileHandle) MoveWorkspaceToTrash(entry *JournalEntry, workspace *Node) error {
+	item := newTrashItem(TrashWorkspace, workspace.Name, workspace.Path)
+	item.Node = workspace.Clone()
+
+	entry.TrackNode(workspace.ID)
+	entry.TrackTrashItem(item.ID)
+	// The tree file is written before the move, so that the workspace can be restored from disk.
+	if _, err := saveWorkspaceTree(workspace); err != nil {
+		return err
+	}
+	if err := mmf.moveTrackedPath(entry, workspace.Path, mmf.trashedFilePath(nil, item)); err != nil {
+		return err
+	}
+	for index, registeredWorkspace := range mmf.Workspaces {
+		if registeredWorkspace.ID == workspace.ID {
+			mmf.Workspaces = append(mmf.Workspaces[:index], mmf.Workspaces[index+1:]...)
+			break
+		}
+	}
+	mmf.Trash = append(mmf.Trash, item)
+
+	return nil
+}
+
+// ListTrash returns the trash items of the active workspace followed by the deleted workspaces.
+func (mmf *MetadataNoteWolfyFileHandle) ListTrash() []*TrashItem {
+	var items []*TrashItem
+	if workspace := mmf.FindActiveWorkspace(); workspace != nil {
+		items = append(items, workspace.Trash...)
+	}
+
+	return append(items, mmf.Trash...)
+}
+
+// RestoreTrashItem moves the item back to its original location, missing parent nodes are recreated.
+func (mmf *MetadataNoteWolfyFileHandle) RestoreTrashItem(entry *JournalEntry, item *TrashItem) error {
+	if item.Kind == TrashWorkspace {
+		return mmf.restoreWorkspace(entry, item)
+	}
+
+	workspace := mmf.FindActiveWorkspace()
+	if workspace == nil {
+		return errors.New("no active workspace, seems like you have not created a workspace yet")
+	}
+	parentNode, err := mmf.restoreNodePath(entry, workspace, item.OriginalPath)
+	if err != nil {
+		return err
+	}
+	targetPath := filepath.Join(parentNode.Path, item.Name)
+	if _, err := os.Lstat(targetPath); err == nil {
+		return fmt.Errorf("%s already exists", targetPath)
+	}
+
+	entry.TrackTrashItem(item.ID)
+	switch item.Kind {
+	case TrashMarkdown:
+		if mmf.FindMarkdown(parentNode, strings.TrimSuffix(item.Name, MarkdownFileExtension)) != nil {
+			return fmt.Errorf("node '%s' already contains a markdown file '%s'", parentNode.Name, item.Name)
+		}
+		entry.TrackMarkdown(item.Markdown.ID)
+		if err := mmf.moveTrackedPath(entry, mmf.trashedFilePath(workspace, item), targetPath); err != nil {
+			return err
+		}
+		mmf.AddMarkdownToNode(parentNode, item.Markdown.Clone())
+	case TrashNode:
+		for _, child := range parentNode.Children {
+			if child.Name == item.Name {
+				return fmt.Errorf("node '%s' already contains a node '%s'", parentNode.Name, item.Name)
+			}
+		}
+		entry.TrackNode(item.Node.ID)
+		if err := mmf.moveTrackedPath(entry, mmf.trashedFilePath(workspace, item), targetPath); err != nil {
+			return err
+		}
+		restoredNode := item.Node.Clone()
+		restoredNode.SetPath(targetPath)
+		parentNode.Children = append(parentNode.Children, restoredNode)
+		mmf.markNodeChanged(parentNode)
+		mmf.markSubtreeChanged(restoredNode)
+	}
+	os.Remove(filepath.Dir(mmf.trashedFilePath(workspace, item)))
+	workspace.Trash = removeTrashItem(workspace.Trash, item.ID)
+	mmf.markNodeChanged(workspace)
+
+	return nil
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) restoreWorkspace(entry *JournalEntry, item *TrashItem) error {
+	if mmf.DoesWorkspaceExist(item.Name) {
+		return fmt.Errorf("a workspace with the name '%s' already exists", item.Name)
+	}
+	if _, err := os.Lstat(item.OriginalPath); err == nil {
+		return fmt.Errorf("%s already exists", item.OriginalPath)
+	}
+
+	entry.TrackTrashItem(item.ID)
+	entry.TrackNode(item.Node.ID)
+	entry.TrackPosition()
+	if err := mmf.moveTrackedPath(entry, mmf.trashedFilePath(nil, item), item.OriginalPath); err != nil {
+		return err
+	}
+	workspace, err := loadWorkspaceTree(item.OriginalPath)
+	if err != nil || workspace == nil {
+		workspace = item.Node.Clone()
+	}
+	workspace.Name = item.Name
+	if _, err := mmf.OpenWorkspace(workspace); err != nil {
+		return err
+	}
+	os.Remove(filepath.Dir(mmf.trashedFilePath(nil, item)))
+	mmf.Trash = removeTrashItem(mmf.Trash, item.ID)
+
+	return nil
+}
+
+// restoreNodePath resolves the node path inside of the workspace and creates the nodes that do not exist anymore.
+func (mmf *MetadataNoteWolfyFileHandle) restoreNodePath(entry *JournalEntry, workspace *Node, nodePath string) (*Node, error) {
+	currentNode := workspace
+	for _, segment := range strings.Split(nodePath, NodePathSeparator) {
+		if segment == "" {
+			continue
+		}
+		var childNode *Node
+		for _, child := range currentNode.Children {
+			if child.Name == segment {
+				childNode = child
+				break
+			}
+		}
+		if childNode == nil {
+			childNode = NewNode(segment, filepath.Join(currentNode.Path, segment))
+			entry.TrackNode(childNode.ID)
+			entry.TrackFile(childNode.Path)
+			if err := os.MkdirAll(childNode.Path, 0750); err != nil {
+				return nil, err
+			}
+			currentNode.Children = append(currentNode.Children, childNode)
+		}
+		currentNode = childNode
+	}
+
+	return currentNode, nil
+}
+
+// EmptyTrash permanently removes the trash items of all workspaces that are older than maxAge,
+// the journal entries that moved them into the trash are dropped.
+func (mmf *MetadataNoteWolfyFileHandle) EmptyTrash(maxAge time.Duration, now time.Time) (int, error) {
+	removedCount := 0
+	var removedPaths []string
+	purge := func(workspace *Node, items []*TrashItem) ([]*TrashItem, error) {
+		var keptItems []*TrashItem
+		for _, item := range items {
+			if now.Sub(item.DeletedAt) < maxAge {
+				keptItems = append(keptItems, item)
+				continue
+			}
+			itemPath := filepath.Dir(mmf.trashedFilePath(workspace, item))
+			if err := os.RemoveAll(itemPath); err != nil {
+				return nil, err
+			}
+			removedPaths = append(removedPaths, itemPath)
+			removedCount++
+		}
+		return keptItems, nil
+	}
+
+	var err error
+	for _, workspace := range mmf.Workspaces {
+		workspace.Trash, err = purge(workspace, workspace.Trash)
+		mmf.markNodeChanged(workspace)
+		if err != nil {
+			mmf.forgetJournalPaths(removedPaths)
+			return removedCount, err
+		}
+	}
+	mmf.Trash, err = purge(nil, mmf.Trash)
+	if forgetErr := mmf.forgetJournalPaths(removedPaths); err == nil {
+		err = forgetErr
+	}
+
+	return removedCount, err
+}
@@ -0,0 +1,152 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashRestoreRecreatesParentNodes(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid.New().String())
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	workspacePath := t.TempDir()
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	err = mmf.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	nodeA := structure.NewNode("A", filepath.Join(workspacePath, "A"))
+	nodeB := structure.NewNode("B", filepath.Join(workspacePath, "A", "B"))
+	nodeA.Children = append(nodeA.Children, nodeB)
+	err = mmf.AddChild(nodeA)
+	assert.NoError(t, err)
+	markdown := structure.NewMarkdown("notes.md")
+	mmf.AddMarkdownToNode(nodeB, markdown)
+	err = os.MkdirAll(nodeB.Path, 0755)
+	assert.NoError(t, err)
+	markdownPath := filepath.Join(nodeB.Path, "notes.md")
+	err = os.WriteFile(markdownPath, []byte("# notes"), 0644)
+	assert.NoError(t, err)
+
+	err = mmf.MoveMarkdownToTrash(nil, nodeB, markdown)
+	assert.NoError(t, err)
+	assert.NoFileExists(t, markdownPath)
+	assert.Empty(t, nodeB.Markdowns)
+	err = mmf.MoveNodeToTrash(nil, nodeB)
+	assert.NoError(t, err)
+	err = mmf.MoveNodeToTrash(nil, nodeA)
+	assert.NoError(t, err)
+	assert.NoDirExists(t, nodeA.Path)
+	assert.Empty(t, mmf.Workspaces[0].Children)
+
+	items := mmf.ListTrash()
+	if assert.Len(t, items, 3) {
+		assert.Equal(t, structure.TrashMarkdown, items[0].Kind)
+		assert.Equal(t, "/A/B", items[0].OriginalPath)
+		assert.Equal(t, "/A", items[1].OriginalPath)
+		assert.Equal(t, "/", items[2].OriginalPath)
+	}
+
+	// Restoring the markdown file recreates the nodes A and B, which are still in the trash
+	entry := mmf.BeginJournalEntry("restore 1")
+	err = mmf.RestoreTrashItem(entry, items[0])
+	assert.NoError(t, err)
+	err = mmf.CommitJournalEntry(entry)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(markdownPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# notes", string(content))
+	restoredNode, err := mmf.ResolveNodePath("/A/B")
+	assert.NoError(t, err)
+	assert.Equal(t, markdown.ID, restoredNode.Markdowns[0].ID)
+	assert.Len(t, mmf.ListTrash(), 2)
+
+	// The original node A cannot be restored next to the recreated one
+	err = mmf.RestoreTrashItem(nil, mmf.ListTrash()[1])
+	assert.Error(t, err)
+
+	// Undo moves the markdown file back into the trash and removes the recreated nodes
+	_, err = mmf.Undo()
+	assert.NoError(t, err)
+	assert.NoDirExists(t, nodeA.Path)
+	assert.Empty(t, mmf.Workspaces[0].Children)
+	assert.Len(t, mmf.ListTrash(), 3)
+
+	err = mmf.RestoreTrashItem(nil, mmf.ListTrash()[2])
+	assert.NoError(t, err)
+	restoredNode, err = mmf.ResolveNodePath("/A")
+	assert.NoError(t, err)
+	assert.Equal(t, nodeA.ID, restoredNode.ID)
+	assert.DirExists(t, nodeA.Path)
+}
+
+func TestTrashWorkspaceAndEmptyTrash(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid.New().String())
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	workspacePath := filepath.Join(t.TempDir(), "test")
+	err := os.Mkdir(workspacePath, 0755)
+	assert.NoError(t, err)
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	err = mmf.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	workspace := mmf.Workspaces[0]
+	markdown := structure.NewMarkdown("notes.md")
+	mmf.AddMarkdownToNode(workspace, markdown)
+	err = os.WriteFile(filepath.Join(workspacePath, "notes.md"), nil, 0644)
+	assert.NoError(t, err)
+	err = mmf.MoveMarkdownToTrash(nil, workspace, markdown)
+	assert.NoError(t, err)
+	err = mmf.Save()
+	assert.NoError(t, err)
+
+	err = mmf.MoveWorkspaceToTrash(nil, workspace)
+	assert.NoError(t, err)
+	err = mmf.Save()
+	assert.NoError(t, err)
+	assert.NoDirExists(t, workspacePath)
+	assert.Empty(t, mmf.Workspaces)
+	items := mmf.ListTrash()
+	assert.Len(t, items, 1)
+
+	// The workspace takes its own trash with it
+	err = mmf.RestoreTrashItem(nil, items[0])
+	assert.NoError(t, err)
+	assert.DirExists(t, workspacePath)
+	assert.Equal(t, "test", mmf.ActiveWorkspace)
+	assert.Equal(t, workspace.ID, mmf.ActiveNode)
+	assert.Len(t, mmf.ListTrash(), 1)
+	err = mmf.Save()
+	assert.NoError(t, err)
+
+	now := time.Now()
+	removedCount, err := mmf.EmptyTrash(time.Hour, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, removedCount)
+	removedCount, err = mmf.EmptyTrash(time.Hour, now.Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removedCount)
+	assert.Empty(t, mmf.ListTrash())
+	entries, err := os.ReadDir(structure.WorkspaceTrashDirPath(workspacePath))
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,70 @@
+package structure
+
+import "fmt"
+
+const (
+	treeBranch     = "├── "
+	treeLastBranch = "└── "
+	treeIndent     = "│   "
+	treeLastIndent = "    "
+)
+
+// TreeLine is a line of the drawn tree, the prefix holds the box-drawing characters in front of the label.
+type TreeLine struct {
+	Prefix   string
+	Label    string
+	IsActive bool
+}
+
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// Tree draws the subtree of the node, every node is labeled with the number of notes below it.
+// Nodes deeper than maxDepth are collapsed into the label of their parent, a maxDepth of 0 draws the whole subtree.
+func (mmf *MetadataNoteWolfyFileHandle) Tree(node *Node, maxDepth int, withNotes bool) []*TreeLine {
+	var lines []*TreeLine
+	var draw func(node *Node, prefix string, childPrefix string, depth int)
+	draw = func(node *Node, prefix string, childPrefix string, depth int) {
+		nodeCount, markdownCount := node.CountDescendants()
+		label := fmt.Sprintf("%s (%s)", node.Name, pluralize(markdownCount, "note"))
+		isCollapsed := maxDepth > 0 && depth >= maxDepth
+		if isCollapsed && nodeCount > 0 {
+			label += fmt.Sprintf(" [%s collapsed]", pluralize(nodeCount, "node"))
+		}
+		lines = append(lines, &TreeLine{Prefix: prefix, Label: label, IsActive: node.ID == mmf.ActiveNode})
+		if isCollapsed {
+			return
+		}
+
+		childCount := len(node.Children)
+		if withNotes {
+			childCount += len(node.Markdowns)
+		}
+		index := 0
+		nextPrefixes := func() (string, string) {
+			index++
+			if index == childCount {
+				return childPrefix + treeLastBranch, childPrefix + treeLastIndent
+			}
+			return childPrefix + treeBranch, childPrefix + treeIndent
+		}
+		for _, child := range node.Children {
+			branchPrefix, indentPrefix := nextPrefixes()
+			draw(child, branchPrefix, indentPrefix, depth+1)
+		}
+		if withNotes {
+			for _, markdown := range node.Markdowns {
+				branchPrefix, _ := nextPrefixes()
+				lines = append(lines, &TreeLine{Prefix: branchPrefix, Label: markdown.Filename})
+			}
+		}
+	}
+	draw(node, "", "", 0)
+
+	return lines
+}
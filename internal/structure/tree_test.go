@@ -0,0 +1,77 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	t.Parallel()
+
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", uuid.New().String())
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	workspacePath := t.TempDir()
+	err = mmf.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	workspace := mmf.Workspaces[0]
+	meetings := structure.NewNode("meetings", filepath.Join(workspacePath, "meetings"))
+	archive := structure.NewNode("archive", filepath.Join(meetings.Path, "archive"))
+	old := structure.NewNode("old", filepath.Join(archive.Path, "old"))
+	projects := structure.NewNode("projects", filepath.Join(workspacePath, "projects"))
+	meetings.Children = append(meetings.Children, archive)
+	archive.Children = append(archive.Children, old)
+	workspace.Children = append(workspace.Children, meetings, projects)
+	mmf.AddMarkdownToNode(meetings, structure.NewMarkdown("weekly.md"))
+	mmf.AddMarkdownToNode(old, structure.NewMarkdown("2024.md"))
+	mmf.AddMarkdownToNode(projects, structure.NewMarkdown("roadmap.md"))
+	mmf.SetActiveNode(archive.ID)
+
+	drawTree := func(node *structure.Node, maxDepth int, withNotes bool) []string {
+		var lines []string
+		for _, line := range mmf.Tree(node, maxDepth, withNotes) {
+			active := ""
+			if line.IsActive {
+				active = " *"
+			}
+			lines = append(lines, line.Prefix+line.Label+active)
+		}
+		return lines
+	}
+
+	assert.Equal(t, []string{
+		"test (3 notes)",
+		"├── meetings (2 notes)",
+		"│   ├── archive (1 note) *",
+		"│   │   └── old (1 note)",
+		"│   │       └── 2024.md",
+		"│   └── weekly.md",
+		"└── projects (1 note)",
+		"    └── roadmap.md",
+	}, drawTree(workspace, 0, true))
+
+	assert.Equal(t, []string{
+		"test (3 notes)",
+		"├── meetings (2 notes)",
+		"│   └── archive (1 note) [1 node collapsed] *",
+		"└── projects (1 note)",
+	}, drawTree(workspace, 2, false))
+
+	assert.Equal(t, []string{
+		"meetings (2 notes)",
+		"├── archive (1 note) [1 node collapsed] *",
+		"└── weekly.md",
+	}, drawTree(meetings, 1, true))
+}
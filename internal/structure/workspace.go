@@ -0,0 +1,222 @@
+package structure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/RaphSku/notewolfy/internal/utility"
+)
+
+const (
+	WorkspaceMetadataDirName = ".notewolfy"
+	WorkspaceTreeFileName    = "tree.json"
+)
+
+// workspaceTree is the content of the tree file inside of a workspace root, all paths are relative to the root,
+// so that the workspace keeps its structure when it is copied or synced to another location.
+type workspaceTree struct {
+	Version   int   `json:"version"`
+	Workspace *Node `json:"workspace"`
+}
+
+func WorkspaceTreeFilePath(workspacePath string) string {
+	return filepath.Join(workspacePath, WorkspaceMetadataDirName, WorkspaceTreeFileName)
+}
+
+func RemoveWorkspaceMetadata(workspacePath string) error {
+	return os.RemoveAll(filepath.Join(workspacePath, WorkspaceMetadataDirName))
+}
+
+func relativizeNode(node *Node, rootPath string) error {
+	absolutePath, err := filepath.Abs(node.Path)
+	if err != nil {
+		return err
+	}
+	relativePath, err := filepath.Rel(rootPath, absolutePath)
+	if err != nil {
+		return err
+	}
+	node.Path = filepath.ToSlash(relativePath)
+	for _, child := range node.Children {
+		if err := relativizeNode(child, rootPath); err != nil {
+			return err
+		}
+	}
+	for _, item := range node.Trash {
+		if item.Node == nil {
+			continue
+		}
+		if err := relativizeNode(item.Node, rootPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func absolutizeNode(node *Node, rootPath string) {
+	if !filepath.IsAbs(node.Path) {
+		node.Path = filepath.Join(rootPath, filepath.FromSlash(node.Path))
+	}
+	for _, child := range node.Children {
+		absolutizeNode(child, rootPath)
+	}
+	for _, item := range node.Trash {
+		if item.Node != nil {
+			absolutizeNode(item.Node, rootPath)
+		}
+	}
+}
+
+// saveWorkspaceTree writes the tree of the workspace into its root, it reports false
+// if the workspace root does not exist or the workspace is adopted, in that case the tree stays in the registry.
+func saveWorkspaceTree(workspace *Node) (bool, error) {
+	fileInfo, err := os.Stat(workspace.Path)
+	if err != nil || !fileInfo.IsDir() || workspace.Adopted {
+		return false, nil
+	}
+
+	rootPath, err := filepath.Abs(workspace.Path)
+	if err != nil {
+		return false, err
+	}
+	relativeWorkspace := workspace.Clone()
+	if err := relativizeNode(relativeWorkspace, rootPath); err != nil {
+		return false, err
+	}
+	data, err := json.MarshalIndent(&workspaceTree{Version: CurrentSchemaVersion, Workspace: relativeWorkspace}, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	data = append(data, '\n')
+
+	treeFilePath := WorkspaceTreeFilePath(workspace.Path)
+	if content, err := os.ReadFile(treeFilePath); err == nil && bytes.Equal(content, data) {
+		return true, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(treeFilePath), 0755); err != nil {
+		return false, err
+	}
+
+	return true, writeFileAtomically(treeFilePath, data, 0644)
+}
+
+// loadWorkspaceTree reads the tree file of the workspace root at the given path, it returns nil
+// if there is no tree file. The returned workspace carries absolute paths.
+func loadWorkspaceTree(workspacePath string) (*Node, error) {
+	content, err := os.ReadFile(WorkspaceTreeFilePath(workspacePath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var treeDocument map[string]any
+	if err := json.Unmarshal(content, &treeDocument); err != nil {
+		return nil, fmt.Errorf("tree file of workspace %s is corrupted: %w", workspacePath, err)
+	}
+	// The tree is migrated as a metadata document with a single workspace, so that every migration applies to it.
+	document := map[string]any{
+		"version":    treeDocument["version"],
+		"workspaces": []any{treeDocument["workspace"]},
+	}
+	version, err := documentVersion(document)
+	if err != nil {
+		return nil, err
+	}
+	if version < CurrentSchemaVersion {
+		// The migrated tree replaces the tree file on the next save, so the original is kept next to it.
+		if err := backupMetadata(WorkspaceTreeFilePath(workspacePath), version, content); err != nil {
+			return nil, err
+		}
+	}
+	if err := MigrateDocument(document); err != nil {
+		return nil, err
+	}
+	content, err = json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	var tree MetadataNoteWolfyFileHandle
+	if err := json.Unmarshal(content, &tree); err != nil {
+		return nil, err
+	}
+	if len(tree.Workspaces) != 1 || tree.Workspaces[0] == nil {
+		return nil, fmt.Errorf("tree file of workspace %s does not contain a workspace", workspacePath)
+	}
+
+	workspace := tree.Workspaces[0]
+	absolutizeNode(workspace, workspacePath)
+
+	return workspace, nil
+}
+
+func (mmf *MetadataNoteWolfyFileHandle) loadWorkspaceTrees() error {
+	for _, workspace := range mmf.Workspaces {
+		tree, err := loadWorkspaceTree(workspace.Path)
+		if err != nil {
+			return err
+		}
+		if tree == nil {
+			continue
+		}
+		if mmf.ActiveNode == workspace.ID {
+			mmf.ActiveNode = tree.ID
+		}
+		workspace.ID = tree.ID
+		workspace.Markdowns = tree.Markdowns
+		workspace.Children = tree.Children
+		workspace.Trash = tree.Trash
+	}
+	if mmf.FindActiveWorkspace() != nil && mmf.FindNode(mmf.ActiveNode) == nil {
+		mmf.ActiveNode = mmf.FindActiveWorkspace().ID
+		mmf.PreviousNode = ""
+	}
+
+	return nil
+}
+
+// FindWorkspaceOnDisk reads the tree file of the workspace root at the given path.
+func FindWorkspaceOnDisk(workspacePath string) (*Node, error) {
+	expandedWorkspacePath, err := utility.ExpandRelativePaths(workspacePath)
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := loadWorkspaceTree(expandedWorkspacePath)
+	if err != nil {
+		return nil, err
+	}
+	if workspace == nil {
+		return nil, fmt.Errorf("no notewolfy workspace found at %s", expandedWorkspacePath)
+	}
+
+	return workspace, nil
+}
+
+// OpenWorkspace registers a workspace found on disk and makes it the active workspace,
+// a workspace that is already registered at the same path is only activated.
+func (mmf *MetadataNoteWolfyFileHandle) OpenWorkspace(workspace *Node) (*Node, error) {
+	for _, registeredWorkspace := range mmf.Workspaces {
+		if registeredWorkspace.Path == workspace.Path {
+			mmf.ActiveWorkspace = registeredWorkspace.Name
+			mmf.ActiveNode = registeredWorkspace.ID
+			mmf.PreviousNode = ""
+			return registeredWorkspace, nil
+		}
+	}
+	for _, registeredWorkspace := range mmf.Workspaces {
+		if registeredWorkspace.ID == workspace.ID {
+			return nil, fmt.Errorf("workspace at %s is already registered as workspace '%s' at %s", workspace.Path, registeredWorkspace.Name, registeredWorkspace.Path)
+		}
+	}
+	if mmf.DoesWorkspaceExist(workspace.Name) {
+		return nil, fmt.Errorf("a workspace with the name '%s' already exists", workspace.Name)
+	}
+	mmf.AddWorkspace(workspace)
+
+	return workspace, nil
+}
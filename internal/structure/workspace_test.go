@@ -0,0 +1,174 @@
+//go:build unit_test
+
+package structure_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/structure"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveWritesWorkspaceTreeIntoRoot(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	workspacePath := t.TempDir()
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	err = mmf.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	childNode := structure.NewNode("child", filepath.Join(workspacePath, "child"))
+	err = mmf.AddChild(childNode)
+	assert.NoError(t, err)
+	mmf.AddMarkdownToNode(childNode, structure.NewMarkdown("note.md"))
+	err = mmf.Save()
+	assert.NoError(t, err)
+
+	// The registry only keeps the location of the workspace
+	content, err := os.ReadFile(metadataFilePath)
+	assert.NoError(t, err)
+	var registry structure.MetadataNoteWolfyFileHandle
+	err = json.Unmarshal(content, &registry)
+	assert.NoError(t, err)
+	assert.Equal(t, workspacePath, registry.Workspaces[0].Path)
+	assert.Empty(t, registry.Workspaces[0].Children)
+
+	// The tree inside of the workspace root uses relative paths
+	content, err = os.ReadFile(structure.WorkspaceTreeFilePath(workspacePath))
+	assert.NoError(t, err)
+	var tree map[string]any
+	err = json.Unmarshal(content, &tree)
+	assert.NoError(t, err)
+	workspace := tree["workspace"].(map[string]any)
+	assert.Equal(t, ".", workspace["path"])
+	assert.Equal(t, "child", workspace["children"].([]any)[0].(map[string]any)["path"])
+
+	actMmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	assert.Equal(t, mmf.Workspaces, actMmf.Workspaces)
+	assert.Equal(t, mmf.ActiveNode, actMmf.ActiveNode)
+}
+
+func TestOpenWorkspaceAtNewLocation(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePathA := fmt.Sprintf("./.notewolfy-%s-a.json", fileID)
+	defer CleanUpFile(metadataFilePathA)
+	metadataFilePathB := fmt.Sprintf("./.notewolfy-%s-b.json", fileID)
+	defer CleanUpFile(metadataFilePathB)
+
+	workspacePath := t.TempDir()
+	mmfA, err := structure.NewMetadataNoteWolfyFileHandle(&structure.Config{MetadataFilePath: metadataFilePathA})
+	assert.NoError(t, err)
+	err = mmfA.AddNewWorkspace("test", workspacePath)
+	assert.NoError(t, err)
+	childNode := structure.NewNode("child", filepath.Join(workspacePath, "child"))
+	err = mmfA.AddChild(childNode)
+	assert.NoError(t, err)
+	err = mmfA.Save()
+	assert.NoError(t, err)
+
+	// We copy the workspace to another location, just like a shared drive would do
+	copiedWorkspacePath := filepath.Join(t.TempDir(), "copy")
+	err = os.CopyFS(copiedWorkspacePath, os.DirFS(workspacePath))
+	assert.NoError(t, err)
+
+	mmfB, err := structure.NewMetadataNoteWolfyFileHandle(&structure.Config{MetadataFilePath: metadataFilePathB})
+	assert.NoError(t, err)
+	workspace, err := structure.FindWorkspaceOnDisk(copiedWorkspacePath)
+	assert.NoError(t, err)
+	workspace, err = mmfB.OpenWorkspace(workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", mmfB.ActiveWorkspace)
+	assert.Equal(t, mmfA.Workspaces[0].ID, workspace.ID)
+	assert.Equal(t, workspace.ID, mmfB.ActiveNode)
+	assert.Equal(t, copiedWorkspacePath, workspace.Path)
+	assert.Equal(t, childNode.ID, workspace.Children[0].ID)
+	assert.Equal(t, filepath.Join(copiedWorkspacePath, "child"), workspace.Children[0].Path)
+
+	_, err = structure.FindWorkspaceOnDisk(filepath.Join(copiedWorkspacePath, "child"))
+	if assert.Error(t, err) {
+		expError := fmt.Errorf("no notewolfy workspace found at %s", filepath.Join(copiedWorkspacePath, "child"))
+		assert.Equal(t, expError, err)
+	}
+}
+
+func TestLegacyRegistryTreeMovesIntoWorkspaceRoot(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+	defer os.Remove(structure.BackupFilePath(metadataFilePath, 2))
+
+	workspacePath := t.TempDir()
+	legacyContent := fmt.Sprintf(`{"version":2,"workspaces":[{"id":"ws","name":"test","path":"%[1]s","markdowns":null,"children":[{"id":"child","name":"child","path":"%[1]s/child","markdowns":null,"children":null}]}],"activeworkspace":"test","activenode":"child","previousnode":""}`, workspacePath)
+	err := os.WriteFile(metadataFilePath, []byte(legacyContent), 0644)
+	assert.NoError(t, err)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "child", mmf.ActiveNode)
+	assert.Equal(t, "child", mmf.Workspaces[0].Children[0].ID)
+	err = mmf.Save()
+	assert.NoError(t, err)
+
+	_, err = os.Stat(structure.WorkspaceTreeFilePath(workspacePath))
+	assert.NoError(t, err)
+	actMmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	assert.Equal(t, mmf.Workspaces, actMmf.Workspaces)
+	assert.Equal(t, "child", actMmf.ActiveNode)
+}
+
+func TestOutdatedWorkspaceTreeIsBackedUp(t *testing.T) {
+	t.Parallel()
+
+	fileID := uuid.New().String()
+	metadataFilePath := fmt.Sprintf("./.notewolfy-%s.json", fileID)
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	workspacePath := t.TempDir()
+	treeFilePath := structure.WorkspaceTreeFilePath(workspacePath)
+	legacyTree := `{"version":3,"workspace":{"id":"ws","name":"test","path":".","markdowns":[{"id":"md","filename":"notes.md"}],"children":null}}`
+	err := os.MkdirAll(filepath.Dir(treeFilePath), 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile(treeFilePath, []byte(legacyTree), 0644)
+	assert.NoError(t, err)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+	workspace, err := structure.FindWorkspaceOnDisk(workspacePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "notes", workspace.Markdowns[0].Title)
+	_, err = mmf.OpenWorkspace(workspace)
+	assert.NoError(t, err)
+	err = mmf.Save()
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(structure.BackupFilePath(treeFilePath, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, legacyTree, string(content))
+	content, err = os.ReadFile(treeFilePath)
+	assert.NoError(t, err)
+	assert.NotEqual(t, legacyTree, string(content))
+}
@@ -0,0 +1,69 @@
+package utility
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadLine reads byte by byte, so that no input after the line ends up in a buffer of ours.
+// The console runs in raw mode, therefore the input is echoed and backspaces are handled here.
+func ReadLine(reader io.Reader) (string, error) {
+	var line []byte
+	buffer := make([]byte, 1)
+	for {
+		n, err := reader.Read(buffer)
+		if n == 1 {
+			switch buffer[0] {
+			case '\r', '\n':
+				return string(line), nil
+			case '\x7f', '\b':
+				if len(line) > 0 {
+					line = line[:len(line)-1]
+					fmt.Print("\b \b")
+				}
+			default:
+				line = append(line, buffer[0])
+				fmt.Print(string(buffer[0]))
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return string(line), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+func AskForConfirmation(reader io.Reader, question string) (bool, error) {
+	fmt.Printf("\n\r%s [y/N] ", question)
+	answer, err := ReadLine(reader)
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes", nil
+}
+
+// AskForChoice asks for a number between 1 and count, an empty answer cancels the choice and returns 0.
+func AskForChoice(reader io.Reader, question string, count int) (int, error) {
+	fmt.Printf("\n\r%s [1-%d] ", question, count)
+	answer, err := ReadLine(reader)
+	if err != nil {
+		return 0, err
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return 0, nil
+	}
+	choice, err := strconv.Atoi(answer)
+	if err != nil || choice < 1 || choice > count {
+		return 0, fmt.Errorf("'%s' is not a number between 1 and %d", answer, count)
+	}
+
+	return choice, nil
+}
@@ -0,0 +1,81 @@
+//go:build unit_test
+
+package utility_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RaphSku/notewolfy/internal/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadLine(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		expLine string
+	}{
+		"line ending with carriage return": {input: "yes\rnext", expLine: "yes"},
+		"line ending with newline":         {input: "no\n", expLine: "no"},
+		"line with backspace":              {input: "yef\x7fs\r", expLine: "yes"},
+		"line without ending":              {input: "y", expLine: "y"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := captureStdOutput(func() {
+				actLine, err := utility.ReadLine(strings.NewReader(tc.input))
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expLine, actLine)
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAskForConfirmation(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  bool
+	}{
+		"confirm with y":    {input: "y\r", want: true},
+		"confirm with YES":  {input: "YES\r", want: true},
+		"decline with n":    {input: "n\r", want: false},
+		"decline with none": {input: "\r", want: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := captureStdOutput(func() {
+				confirmed, err := utility.AskForConfirmation(strings.NewReader(tc.input), "Continue?")
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, confirmed)
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAskForChoice(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		"choose the second entry": {input: "2\r", want: 2},
+		"cancel with none":        {input: "\r", want: 0},
+		"choice out of range":     {input: "4\r", wantErr: true},
+		"choice is no number":     {input: "two\r", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := captureStdOutput(func() {
+				choice, err := utility.AskForChoice(strings.NewReader(tc.input), "Pick one", 3)
+				assert.Equal(t, tc.wantErr, err != nil)
+				assert.Equal(t, tc.want, choice)
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -2,9 +2,14 @@ package utility
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 )
 
 func GetHomeDir() (string, error) {
@@ -18,13 +23,13 @@ func GetHomeDir() (string, error) {
 
 func ExpandRelativePaths(path string) (string, error) {
 	expandedPath := path
-	if path[0] == '~' {
+	if strings.HasPrefix(path, "~") {
 		homeDir, err := GetHomeDir()
 		if err != nil {
 			return "", err
 		}
 		expandedPath = filepath.Join(homeDir, path[1:])
-	} else if path[:2] == ".." || path[0] == '.' {
+	} else if strings.HasPrefix(path, ".") {
 		var err error
 		expandedPath, err = filepath.Abs(path)
 		if err != nil {
@@ -50,3 +55,151 @@ func DoesChildPathMatchesParentPath(parentPath string, childPath string) (bool,
 
 	return true, nil
 }
+
+// MovePath renames the file or directory and falls back to copying it, if source and target are on different devices.
+func MovePath(sourcePath string, targetPath string) error {
+	if _, err := os.Lstat(targetPath); err == nil {
+		return fmt.Errorf("%s already exists", targetPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return err
+	}
+	err := os.Rename(sourcePath, targetPath)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := CopyPath(sourcePath, targetPath); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(sourcePath)
+}
+
+// CopyPath copies the file or the directory with all of its content, a partial copy is removed again on failure.
+func CopyPath(sourcePath string, targetPath string) error {
+	if _, err := os.Lstat(targetPath); err == nil {
+		return fmt.Errorf("%s already exists", targetPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return err
+	}
+	fileInfo, err := os.Stat(sourcePath)
+	if err != nil {
+		return err
+	}
+	if fileInfo.IsDir() {
+		err = os.CopyFS(targetPath, os.DirFS(sourcePath))
+	} else {
+		var content []byte
+		content, err = os.ReadFile(sourcePath)
+		if err == nil {
+			err = os.WriteFile(targetPath, content, fileInfo.Mode().Perm())
+		}
+	}
+	if err != nil {
+		os.RemoveAll(targetPath)
+	}
+
+	return err
+}
+
+// ParseAge accepts the units of time.ParseDuration and additionally 'd' for days, e.g. 30d or 12h.
+func ParseAge(age string) (time.Duration, error) {
+	if days, isDays := strings.CutSuffix(age, "d"); isDays {
+		dayCount, err := strconv.Atoi(days)
+		if err != nil || dayCount < 0 {
+			return 0, fmt.Errorf("age '%s' is not a valid number of days", age)
+		}
+		return time.Duration(dayCount) * 24 * time.Hour, nil
+	}
+	if age == "0" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(age)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("age '%s' is not a valid duration, use e.g. 30d or 12h", age)
+	}
+
+	return duration, nil
+}
+
+// DiskUsage sums up the sizes of all files below the path, including the path itself.
+func DiskUsage(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += fileInfo.Size()
+		return nil
+	})
+
+	return size, err
+}
+
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	divisor, exponent := int64(unit), 0
+	for remainder := size / unit; remainder >= unit; remainder /= unit {
+		divisor *= unit
+		exponent++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(divisor), "KMGTPE"[exponent])
+}
+
+// SplitCommandLine splits a command like `code --wait` into its arguments, single and double quotes group
+// arguments with spaces and a backslash escapes the next character outside of single quotes.
+func SplitCommandLine(commandLine string) ([]string, error) {
+	var arguments []string
+	var argument strings.Builder
+	isArgument := false
+	var quote rune
+	isEscaped := false
+	for _, character := range commandLine {
+		switch {
+		case isEscaped:
+			argument.WriteRune(character)
+			isEscaped = false
+		case character == '\\' && quote != '\'':
+			isEscaped = true
+			isArgument = true
+		case quote != 0:
+			if character == quote {
+				quote = 0
+			} else {
+				argument.WriteRune(character)
+			}
+		case character == '\'' || character == '"':
+			quote = character
+			isArgument = true
+		case character == ' ' || character == '\t':
+			if isArgument {
+				arguments = append(arguments, argument.String())
+				argument.Reset()
+				isArgument = false
+			}
+		default:
+			argument.WriteRune(character)
+			isArgument = true
+		}
+	}
+	if quote != 0 || isEscaped {
+		return nil, fmt.Errorf("command '%s' has an unterminated quote or escape", commandLine)
+	}
+	if isArgument {
+		arguments = append(arguments, argument.String())
+	}
+
+	return arguments, nil
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/RaphSku/notewolfy/internal/utility"
 	"github.com/stretchr/testify/assert"
@@ -44,6 +45,7 @@ func TestExpandRelativePaths(t *testing.T) {
 		{name: "Check tilde relative path", givenPath: "~/tmp", expPath: expTildePath},
 		{name: "Check dot relative path", givenPath: "./tmp", expPath: expDotPath},
 		{name: "Check previous directory relative path", givenPath: "../tmp", expPath: expPrevPath},
+		{name: "Check short path", givenPath: "x", expPath: "x"},
 	}
 
 	for _, tc := range testCases {
@@ -85,3 +87,94 @@ func TestDoesChildPathMatchesParentPath(t *testing.T) {
 		})
 	}
 }
+
+func TestMovePath(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "source.md")
+	err := os.WriteFile(sourcePath, []byte("# notes"), 0644)
+	assert.NoError(t, err)
+
+	targetPath := filepath.Join(tempDir, "trash", "id", "source.md")
+	err = utility.MovePath(sourcePath, targetPath)
+	assert.NoError(t, err)
+	assert.NoFileExists(t, sourcePath)
+	content, err := os.ReadFile(targetPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# notes", string(content))
+
+	err = os.WriteFile(sourcePath, []byte("# other notes"), 0644)
+	assert.NoError(t, err)
+	err = utility.MovePath(sourcePath, targetPath)
+	assert.EqualError(t, err, targetPath+" already exists")
+}
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		age    string
+		expAge time.Duration
+		expErr bool
+	}{
+		{name: "Check days", age: "30d", expAge: 30 * 24 * time.Hour},
+		{name: "Check hours", age: "12h", expAge: 12 * time.Hour},
+		{name: "Check zero", age: "0", expAge: 0},
+		{name: "Check invalid days", age: "xd", expErr: true},
+		{name: "Check negative duration", age: "-1h", expErr: true},
+		{name: "Check invalid duration", age: "forever", expErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actAge, err := utility.ParseAge(tc.age)
+			if tc.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expAge, actAge)
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "512 B", utility.FormatBytes(512))
+	assert.Equal(t, "1.5 KiB", utility.FormatBytes(1536))
+	assert.Equal(t, "2.0 MiB", utility.FormatBytes(2*1024*1024))
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		commandLine  string
+		expArguments []string
+		expErr       bool
+	}{
+		{name: "Check single command", commandLine: "vim", expArguments: []string{"vim"}},
+		{name: "Check arguments", commandLine: "  code   --wait ", expArguments: []string{"code", "--wait"}},
+		{name: "Check quotes", commandLine: `"/opt/my editor/bin" -c 'set spell' ""`, expArguments: []string{"/opt/my editor/bin", "-c", "set spell", ""}},
+		{name: "Check escapes", commandLine: `my\ editor 'a\b' "\""`, expArguments: []string{"my editor", `a\b`, `"`}},
+		{name: "Check empty command", commandLine: " ", expArguments: nil},
+		{name: "Check unterminated quote", commandLine: `code "--wait`, expErr: true},
+		{name: "Check unterminated escape", commandLine: `code \`, expErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actArguments, err := utility.SplitCommandLine(tc.commandLine)
+			if tc.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expArguments, actArguments)
+		})
+	}
+}
@@ -27,6 +27,12 @@ func CleanUpFile(filePath string) {
 		fmt.Println("Could not remove metadata file! Please clean it up yourself!")
 		os.Exit(1)
 	}
+	os.Remove(filePath + ".lock")
+	os.Remove(filePath + ".journal")
+	os.RemoveAll(filePath + ".trash")
+	os.Remove(filePath + ".results")
+	os.Remove(filePath + ".index")
+	os.Remove(filePath + ".index.log")
 }
 
 func TestNodeCreatingAndDeleting(t *testing.T) {
@@ -57,11 +63,12 @@ func TestNodeCreatingAndDeleting(t *testing.T) {
 	defer os.RemoveAll(workspacePath)
 	assert.NoError(t, err)
 	workspaceNode := &structure.Node{
+		ID:   uuid.New().String(),
 		Name: "Workspace",
 		Path: workspacePath,
 	}
 	mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
-	mmf.ActiveNode = workspaceNode.Name
+	mmf.ActiveNode = workspaceNode.ID
 	mmf.ActiveWorkspace = workspaceNode.Name
 	err = mmf.Save()
 	assert.NoError(t, err)
@@ -85,12 +92,12 @@ func TestNodeCreatingAndDeleting(t *testing.T) {
 	// 4. Use command `goto` to move to test node
 	statement = fmt.Sprintf("goto %s", testNodeName)
 	commands.MatchStatementToCommand(mmf, statement)
-	assert.Equal(t, testNodeName, mmf.ActiveNode)
+	assert.Equal(t, testNodeName, mmf.FindNode(mmf.ActiveNode).Name)
 
 	// 5. Use command `goback` to move back to the workspace node
 	statement = "goback"
 	commands.MatchStatementToCommand(mmf, statement)
-	assert.Equal(t, workspaceNode.Name, mmf.ActiveNode)
+	assert.Equal(t, workspaceNode.ID, mmf.ActiveNode)
 
 	// 6. Delete test node
 	statement = fmt.Sprintf("delete node %s", testNodeName)
@@ -102,3 +109,69 @@ func TestNodeCreatingAndDeleting(t *testing.T) {
 	assert.Equal(t, 1, len(mmf.Workspaces[0].Children))
 	assert.Equal(t, nodeName, mmf.Workspaces[0].Children[0].Name)
 }
+
+func TestNodePathNavigation(t *testing.T) {
+	// Scenario:
+	// 1. Prepare workspace with the nodes research/papers
+	// 2. Create a markdown file via a relative node path
+	// 3. Use command `goto` with a nested path
+	// 4. Use command `goto -` to jump to the previous node
+	// 5. Use command `goto /` to jump to the workspace root
+	// 6. Delete the markdown file and the node via paths
+	t.Parallel()
+
+	metadataFilePath := createUniquePath("./.notewolfy")
+	config := &structure.Config{
+		MetadataFilePath: metadataFilePath,
+	}
+	defer CleanUpFile(metadataFilePath)
+
+	mmf, err := structure.NewMetadataNoteWolfyFileHandle(config)
+	assert.NoError(t, err)
+
+	// 1. Prepare workspace with the nodes research/papers
+	workspacePath, err := utility.ExpandRelativePaths(createUniquePath("./tmp"))
+	assert.NoError(t, err)
+	err = os.Mkdir(workspacePath, os.ModePerm)
+	defer os.RemoveAll(workspacePath)
+	assert.NoError(t, err)
+	workspaceNode := structure.NewNode("Workspace", workspacePath)
+	mmf.Workspaces = append(mmf.Workspaces, workspaceNode)
+	mmf.ActiveNode = workspaceNode.ID
+	mmf.ActiveWorkspace = workspaceNode.Name
+	err = mmf.Save()
+	assert.NoError(t, err)
+	commands.MatchStatementToCommand(mmf, "create node research")
+	commands.MatchStatementToCommand(mmf, "goto research")
+	commands.MatchStatementToCommand(mmf, "create node papers")
+	commands.MatchStatementToCommand(mmf, "goto ..")
+	assert.Equal(t, mmf.Workspaces[0].ID, mmf.ActiveNode)
+
+	// 2. Create a markdown file via a relative node path
+	commands.MatchStatementToCommand(mmf, "create md research/papers/note")
+	papersPath := filepath.Join(workspacePath, "research", "papers")
+	assert.FileExists(t, filepath.Join(papersPath, "note.md"))
+
+	// 3. Use command `goto` with a nested path
+	commands.MatchStatementToCommand(mmf, "goto research/papers")
+	papersNode := mmf.FindNode(mmf.ActiveNode)
+	assert.Equal(t, "papers", papersNode.Name)
+	assert.Equal(t, "note.md", papersNode.Markdowns[0].Filename)
+
+	// 4. Use command `goto -` to jump to the previous node
+	commands.MatchStatementToCommand(mmf, "goto -")
+	assert.Equal(t, mmf.Workspaces[0].ID, mmf.ActiveNode)
+	commands.MatchStatementToCommand(mmf, "goto -")
+	assert.Equal(t, papersNode.ID, mmf.ActiveNode)
+
+	// 5. Use command `goto /` to jump to the workspace root
+	commands.MatchStatementToCommand(mmf, "goto /")
+	assert.Equal(t, mmf.Workspaces[0].ID, mmf.ActiveNode)
+
+	// 6. Delete the markdown file and the node via paths
+	commands.MatchStatementToCommand(mmf, "delete md /research/papers/note")
+	assert.NoFileExists(t, filepath.Join(papersPath, "note.md"))
+	commands.MatchStatementToCommand(mmf, "delete node research/papers")
+	assert.NoDirExists(t, papersPath)
+	assert.Empty(t, mmf.Workspaces[0].Children[0].Children)
+}